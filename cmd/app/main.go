@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"proxy/internal/oob"
+	"proxy/internal/passive"
 	"proxy/internal/proxy/delivery"
 	webapidelivery "proxy/internal/web-api/delivery"
 	"proxy/internal/web-api/repository"
+	"proxy/internal/web-api/repository/disk"
+	"proxy/internal/web-api/repository/memory"
+	"proxy/internal/web-api/usecases"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -18,6 +26,16 @@ const (
 	certPath = "server.crt"
 	keyPath  = "server.key"
 	Port     = "8000"
+
+	defaultMemoryCapacity = 10000
+	defaultStoragePath    = "data"
+
+	defaultRetentionInterval = time.Minute
+
+	defaultOOBHost     = "127.0.0.1"
+	defaultOOBDomain   = "oob.test"
+	defaultOOBHTTPPort = "8081"
+	defaultOOBDNSPort  = "8053"
 )
 
 type Server struct {
@@ -32,26 +50,124 @@ func main() {
 		return
 	}
 
-	redisClient := redis.NewClient(
-		&redis.Options{
-			Addr:     fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
-			Password: os.Getenv("REDIS_PASSWORD"),
-			DB:       0,
-		},
-	)
+	repo, err := newProjectsRepository()
+	if err != nil {
+		log.Println("Error creating storage", err)
+
+		return
+	}
+
+	projects, err := usecases.NewProjects(repo)
+	if err != nil {
+		log.Println("Error opening project", err)
+
+		return
+	}
+
+	proxyHandler := delivery.NewProxyHandler(certPath, keyPath, projects)
+
+	retentionInterval := defaultRetentionInterval
+	if value := os.Getenv("RETENTION_INTERVAL"); value != "" {
+		retentionInterval, err = time.ParseDuration(value)
+		if err != nil || retentionInterval <= 0 {
+			log.Println("Invalid RETENTION_INTERVAL", value)
+
+			return
+		}
+	}
+
+	pruner := usecases.NewPruner(projects, retentionInterval)
+	go pruner.Run(context.Background())
+
+	listener := oob.NewListener(envOrDefault("OOB_HOST", defaultOOBHost), envOrDefault("OOB_DOMAIN", defaultOOBDomain),
+		envOrDefault("OOB_HTTP_PORT", defaultOOBHTTPPort), envOrDefault("OOB_DNS_PORT", defaultOOBDNSPort))
+
+	go func() {
+		err := listener.ListenAndServeHTTP()
+		if err != nil {
+			log.Println("Error running out-of-band HTTP listener", err)
+		}
+	}()
+
+	go func() {
+		err := listener.ListenAndServeDNS()
+		if err != nil {
+			log.Println("Error running out-of-band DNS listener", err)
+		}
+	}()
+
+	fuzzer := usecases.NewFuzzer(projects, proxyHandler, listener)
 
-	storage := repository.NewStorage(redisClient)
+	scanner := usecases.NewScanner(projects, proxyHandler, listener)
 
-	proxyHandler := delivery.NewProxyHandler(certPath, keyPath, storage)
-	handler := webapidelivery.NewHandler(storage, proxyHandler)
+	siteMap := usecases.NewSiteMap(projects)
+
+	signatures := passive.DefaultSignatures()
+	if path := os.Getenv("SECRET_SIGNATURES"); path != "" {
+		signatures, err = passive.LoadSignatures(path)
+		if err != nil {
+			log.Println("Error loading secret signatures", err)
+
+			return
+		}
+	}
+
+	analyzer, err := passive.NewAnalyzer(signatures)
+	if err != nil {
+		log.Println("Error compiling secret signatures", err)
+
+		return
+	}
+
+	passiveScanner := usecases.NewPassiveScanner(projects, analyzer)
+	go passiveScanner.Run(context.Background())
+
+	handler := webapidelivery.NewHandler(projects, proxyHandler, pruner, fuzzer, scanner, siteMap, listener)
 
 	router := mux.NewRouter()
 	rootRouter := router.PathPrefix("/api").Subrouter()
 
+	rootRouter.HandleFunc("/requests", handler.DeleteRequests).Methods(http.MethodDelete)
+	rootRouter.HandleFunc("/requests/{id}", handler.DeleteRequest).Methods(http.MethodDelete)
 	rootRouter.HandleFunc("/requests", handler.GetRequestsList)
 	rootRouter.HandleFunc("/requests/{id}", handler.GetRequest)
-	rootRouter.HandleFunc("/repeat/{id}", handler.RepeatRequest)
-	rootRouter.HandleFunc("/scan/{id}", handler.ScanRequest)
+	rootRouter.HandleFunc("/requests/{id}/export", handler.ExportRequest)
+	rootRouter.HandleFunc("/requests/{id}/tree", handler.GetRevisionTree)
+	rootRouter.HandleFunc("/requests/{id}/points", handler.GetInsertionPoints)
+	rootRouter.HandleFunc("/requests/{id}/body", handler.GetBody)
+	rootRouter.HandleFunc("/repeat/{id}", handler.RepeatRequest).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/scan/{id}", handler.ScanRequest).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/scans", handler.GetScans)
+	rootRouter.HandleFunc("/scans/{id}", handler.DeleteScan).Methods(http.MethodDelete)
+	rootRouter.HandleFunc("/scans/{id}", handler.GetScan)
+	rootRouter.HandleFunc("/scans/{id}/stop", handler.StopScan).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/search", handler.Search)
+	rootRouter.HandleFunc("/sitemap", handler.GetSiteMap)
+	rootRouter.HandleFunc("/issues", handler.GetIssues)
+	rootRouter.HandleFunc("/issues/{id}", handler.GetIssue)
+	rootRouter.HandleFunc("/interactions", handler.GetInteractions)
+	rootRouter.HandleFunc("/interactions/tokens", handler.CreateInteractionToken).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/interactions/tokens/{token}", handler.GetInteractionToken)
+	rootRouter.HandleFunc("/diff", handler.Diff)
+	rootRouter.HandleFunc("/fuzz", handler.StartAttack).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/fuzz", handler.GetAttacks)
+	rootRouter.HandleFunc("/fuzz/{id}", handler.DeleteAttack).Methods(http.MethodDelete)
+	rootRouter.HandleFunc("/fuzz/{id}", handler.GetAttack)
+	rootRouter.HandleFunc("/fuzz/{id}/stop", handler.StopAttack).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/payloads/preview", handler.PreviewPayload).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/export/har", handler.ExportHAR)
+	rootRouter.HandleFunc("/import", handler.ImportTraffic).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/scope", handler.SaveScope).Methods(http.MethodPut)
+	rootRouter.HandleFunc("/scope", handler.GetScope)
+	rootRouter.HandleFunc("/retention", handler.SaveRetentionPolicy).Methods(http.MethodPut)
+	rootRouter.HandleFunc("/retention", handler.GetRetentionPolicy)
+	rootRouter.HandleFunc("/retention/prune", handler.PruneHistory).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/projects", handler.CreateProject).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/projects", handler.GetProjects)
+	rootRouter.HandleFunc("/projects/restore", handler.RestoreProject).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/projects/{name}", handler.DeleteProject).Methods(http.MethodDelete)
+	rootRouter.HandleFunc("/projects/{name}/switch", handler.SwitchProject).Methods(http.MethodPost)
+	rootRouter.HandleFunc("/projects/{name}/archive", handler.ArchiveProject)
 
 	srv := new(Server)
 
@@ -76,3 +192,58 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+func envOrDefault(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+func newProjectsRepository() (usecases.ProjectsRepository, error) {
+	switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+	case "", "redis":
+		redisClient := redis.NewClient(
+			&redis.Options{
+				Addr:     fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT")),
+				Password: os.Getenv("REDIS_PASSWORD"),
+				DB:       0,
+			},
+		)
+
+		return repository.NewProjects(redisClient), nil
+	case "memory":
+		capacity := defaultMemoryCapacity
+
+		if value := os.Getenv("MEMORY_CAPACITY"); value != "" {
+			var err error
+
+			capacity, err = strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid MEMORY_CAPACITY %q", value)
+			}
+		}
+
+		projects, err := memory.NewProjects(capacity)
+		if err != nil {
+			return nil, err
+		}
+
+		return projects, nil
+	case "disk":
+		path := os.Getenv("STORAGE_PATH")
+		if path == "" {
+			path = defaultStoragePath
+		}
+
+		projects, err := disk.NewProjects(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return projects, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
@@ -0,0 +1,114 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+type Change struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	Old  any    `json:"old"`
+	New  any    `json:"new"`
+}
+
+func ParseJSON(data []byte) (any, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+
+	err := decoder.Decode(&value)
+	if err != nil || decoder.More() {
+		return nil, false
+	}
+
+	return value, true
+}
+
+func JSON(a, b any) []*Change {
+	return compareJSON("", a, b, make([]*Change, 0))
+}
+
+func compareJSON(path string, a, b any, changes []*Change) []*Change {
+	switch aValue := a.(type) {
+	case map[string]any:
+		bValue, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(aValue)+len(bValue))
+		for key := range aValue {
+			keys = append(keys, key)
+		}
+
+		for key := range bValue {
+			if _, ok := aValue[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			changes = compareMember(path+"/"+escapePointer(key), aValue, bValue, key, changes)
+		}
+
+		return changes
+	case []any:
+		bValue, ok := b.([]any)
+		if !ok {
+			break
+		}
+
+		for i := 0; i < max(len(aValue), len(bValue)); i++ {
+			itemPath := path + "/" + strconv.Itoa(i)
+
+			switch {
+			case i >= len(aValue):
+				changes = append(changes, &Change{Path: itemPath, Kind: ChangeAdded, New: bValue[i]})
+			case i >= len(bValue):
+				changes = append(changes, &Change{Path: itemPath, Kind: ChangeRemoved, Old: aValue[i]})
+			default:
+				changes = compareJSON(itemPath, aValue[i], bValue[i], changes)
+			}
+		}
+
+		return changes
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		changes = append(changes, &Change{Path: path, Kind: ChangeChanged, Old: a, New: b})
+	}
+
+	return changes
+}
+
+func compareMember(path string, a, b map[string]any, key string, changes []*Change) []*Change {
+	aValue, inA := a[key]
+	bValue, inB := b[key]
+
+	switch {
+	case !inA:
+		return append(changes, &Change{Path: path, Kind: ChangeAdded, New: bValue})
+	case !inB:
+		return append(changes, &Change{Path: path, Kind: ChangeRemoved, Old: aValue})
+	default:
+		return compareJSON(path, aValue, bValue, changes)
+	}
+}
+
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
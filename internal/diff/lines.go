@@ -0,0 +1,149 @@
+package diff
+
+import "strings"
+
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+
+	// maxEdits bounds the Myers search; larger differences are reported as a full replacement.
+	maxEdits = 1000
+)
+
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+func SplitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+}
+
+func Lines(a, b []string) []*Line {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]*Line, 0, len(a)+len(b))
+	lines = appendLines(lines, OpEqual, a[:prefix])
+
+	middle, ok := myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	if !ok {
+		middle = appendLines(nil, OpDelete, a[prefix:len(a)-suffix])
+		middle = appendLines(middle, OpInsert, b[prefix:len(b)-suffix])
+	}
+
+	lines = append(lines, middle...)
+
+	return appendLines(lines, OpEqual, a[len(a)-suffix:])
+}
+
+func appendLines(lines []*Line, op string, texts []string) []*Line {
+	for _, text := range texts {
+		lines = append(lines, &Line{Op: op, Text: text})
+	}
+
+	return lines
+}
+
+// myers returns the shortest edit script between a and b, keeping for every step only the
+// diagonals reachable so far so that memory stays quadratic in the number of edits.
+func myers(a, b []string) ([]*Line, bool) {
+	n, m := len(a), len(b)
+
+	if n == 0 || m == 0 {
+		return appendLines(appendLines(nil, OpDelete, a), OpInsert, b), true
+	}
+
+	limit := min(n+m, maxEdits)
+	v := map[int]int{1: 0}
+	trace := make([]map[int]int, 0)
+
+	for d := 0; d <= limit; d++ {
+		snapshot := make(map[int]int, len(v))
+		for k, x := range v {
+			snapshot[k] = x
+		}
+
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1] < v[k+1]) {
+				x = v[k+1]
+			} else {
+				x = v[k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[k] = x
+
+			if x >= n && y >= m {
+				return backtrack(a, b, trace), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func backtrack(a, b []string, trace []map[int]int) []*Line {
+	x, y := len(a), len(b)
+	reversed := make([]*Line, 0, x+y)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[k-1] < v[k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, &Line{Op: OpEqual, Text: a[x]})
+		}
+
+		if d == 0 {
+			break
+		}
+
+		if x == prevX {
+			y--
+			reversed = append(reversed, &Line{Op: OpInsert, Text: b[y]})
+		} else {
+			x--
+			reversed = append(reversed, &Line{Op: OpDelete, Text: a[x]})
+		}
+	}
+
+	lines := make([]*Line, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+
+	return lines
+}
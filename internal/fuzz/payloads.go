@@ -0,0 +1,173 @@
+package fuzz
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"proxy/internal/web-api/models"
+	"strconv"
+	"strings"
+)
+
+const (
+	MaxPayloads = 100000
+
+	defaultCharsetLength = 1
+	maxCharsetLength     = 8
+)
+
+var (
+	ErrUnknownPayload = errors.New("unknown payload source")
+	ErrBadPayload     = errors.New("invalid payload source")
+)
+
+// Capture returns values previously seen in history for a request field such as a query parameter.
+type Capture func(field, name string) ([]string, error)
+
+func Expand(source *models.PayloadSource, capture Capture) ([]string, error) {
+	switch source.Type {
+	case models.PayloadList:
+		return wordlist(source)
+	case models.PayloadNumbers:
+		return numbers(source)
+	case models.PayloadCharset:
+		return charset(source)
+	case models.PayloadCaptured:
+		if source.Field == "" || source.Name == "" {
+			return nil, ErrBadPayload
+		}
+
+		values, err := capture(source.Field, source.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return limit(values)
+	default:
+		return nil, ErrUnknownPayload
+	}
+}
+
+func limit(values []string) ([]string, error) {
+	if len(values) > MaxPayloads {
+		return nil, ErrTooManyRequests
+	}
+
+	return values, nil
+}
+
+func wordlist(source *models.PayloadSource) ([]string, error) {
+	if source.Path == "" {
+		return limit(source.Values)
+	}
+
+	words, err := ReadWordlist(source.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return limit(append(words, source.Values...))
+}
+
+func ReadWordlist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	words := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		word := strings.TrimRight(scanner.Text(), "\r")
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+
+		words = append(words, word)
+
+		if len(words) > MaxPayloads {
+			return nil, ErrTooManyRequests
+		}
+	}
+
+	return words, scanner.Err()
+}
+
+func numbers(source *models.PayloadSource) ([]string, error) {
+	step := source.Step
+	if step == 0 {
+		step = 1
+	}
+
+	if (step > 0 && source.From > source.To) || (step < 0 && source.From < source.To) {
+		return nil, ErrBadPayload
+	}
+
+	// The distance between int64 bounds always fits in uint64, while To-From may overflow int64.
+	span, stride := uint64(source.To)-uint64(source.From), uint64(step)
+	if step < 0 {
+		span, stride = uint64(source.From)-uint64(source.To), -uint64(step)
+	}
+
+	if span/stride >= MaxPayloads {
+		return nil, ErrTooManyRequests
+	}
+
+	count := int64(span/stride) + 1
+
+	values := make([]string, 0, count)
+	for i := int64(0); i < count; i++ {
+		values = append(values, strconv.FormatInt(source.From+i*step, 10))
+	}
+
+	return values, nil
+}
+
+func charset(source *models.PayloadSource) ([]string, error) {
+	alphabet := []rune(source.Charset)
+	if len(alphabet) == 0 {
+		return nil, ErrBadPayload
+	}
+
+	minLength := max(source.MinLength, defaultCharsetLength)
+	maxLength := max(source.MaxLength, minLength)
+
+	if maxLength > maxCharsetLength {
+		return nil, ErrBadPayload
+	}
+
+	values := make([]string, 0)
+
+	for length := minLength; length <= maxLength; length++ {
+		indices := make([]int, length)
+
+		for {
+			if len(values) >= MaxPayloads {
+				return nil, ErrTooManyRequests
+			}
+
+			word := make([]rune, length)
+			for i, index := range indices {
+				word[i] = alphabet[index]
+			}
+
+			values = append(values, string(word))
+
+			position := length - 1
+			for position >= 0 && indices[position] == len(alphabet)-1 {
+				indices[position] = 0
+				position--
+			}
+
+			if position < 0 {
+				break
+			}
+
+			indices[position]++
+		}
+	}
+
+	return values, nil
+}
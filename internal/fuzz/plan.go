@@ -0,0 +1,106 @@
+package fuzz
+
+import (
+	"errors"
+	"proxy/internal/web-api/models"
+	"slices"
+)
+
+const MaxRequests = 100000
+
+var (
+	ErrUnknownMode     = errors.New("unknown attack mode")
+	ErrPayloadSets     = errors.New("wrong number of payload sets for attack mode")
+	ErrTooManyRequests = errors.New("attack exceeds the request limit")
+)
+
+// Plan enumerates the requests of an attack by index, so that huge cluster bombs are never materialized.
+type Plan struct {
+	mode     string
+	defaults []string
+	sets     [][]string
+	total    int
+}
+
+func NewPlan(mode string, defaults []string, sets [][]string) (*Plan, error) {
+	plan := &Plan{mode: mode, defaults: defaults, sets: sets}
+
+	switch mode {
+	case models.AttackSniper, models.AttackBatteringRam:
+		if len(sets) != 1 {
+			return nil, ErrPayloadSets
+		}
+
+		plan.total = len(sets[0])
+		if mode == models.AttackSniper {
+			plan.total *= len(defaults)
+		}
+	case models.AttackPitchfork:
+		if len(sets) != len(defaults) {
+			return nil, ErrPayloadSets
+		}
+
+		plan.total = len(sets[0])
+		for _, set := range sets[1:] {
+			plan.total = min(plan.total, len(set))
+		}
+	case models.AttackClusterBomb:
+		if len(sets) != len(defaults) {
+			return nil, ErrPayloadSets
+		}
+
+		plan.total = 1
+		for _, set := range sets {
+			if len(set) > 0 && plan.total > MaxRequests/len(set) {
+				return nil, ErrTooManyRequests
+			}
+
+			plan.total *= len(set)
+		}
+	default:
+		return nil, ErrUnknownMode
+	}
+
+	if plan.total > MaxRequests {
+		return nil, ErrTooManyRequests
+	}
+
+	return plan, nil
+}
+
+func (plan *Plan) Len() int {
+	return plan.total
+}
+
+// At returns the values for every position of request i, the position a sniper attack
+// targets and the payloads that were inserted.
+func (plan *Plan) At(i int) ([]string, int, []string) {
+	values := slices.Clone(plan.defaults)
+
+	switch plan.mode {
+	case models.AttackSniper:
+		set := plan.sets[0]
+		position := i / len(set)
+		values[position] = set[i%len(set)]
+
+		return values, position, []string{values[position]}
+	case models.AttackBatteringRam:
+		for position := range values {
+			values[position] = plan.sets[0][i]
+		}
+
+		return values, 0, []string{plan.sets[0][i]}
+	case models.AttackPitchfork:
+		for position := range values {
+			values[position] = plan.sets[position][i]
+		}
+	case models.AttackClusterBomb:
+		for position := len(values) - 1; position >= 0; position-- {
+			set := plan.sets[position]
+			values[position] = set[i%len(set)]
+			i /= len(set)
+		}
+	}
+
+	return values, 0, slices.Clone(values)
+}
@@ -0,0 +1,132 @@
+package fuzz
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"proxy/internal/web-api/models"
+	"strings"
+)
+
+var ErrUnknownProcessor = errors.New("unknown payload processor")
+
+func Process(payload string, processors []*models.PayloadProcessor) (string, error) {
+	steps, err := ProcessSteps(payload, processors)
+	if err != nil {
+		return "", err
+	}
+
+	if len(steps) == 0 {
+		return payload, nil
+	}
+
+	return steps[len(steps)-1], nil
+}
+
+// ProcessSteps applies the chain in order and returns the payload after every processor.
+func ProcessSteps(payload string, processors []*models.PayloadProcessor) ([]string, error) {
+	steps := make([]string, 0, len(processors))
+
+	for _, processor := range processors {
+		var err error
+
+		payload, err = apply(payload, processor)
+		if err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, payload)
+	}
+
+	return steps, nil
+}
+
+func apply(payload string, processor *models.PayloadProcessor) (string, error) {
+	switch processor.Type {
+	case models.ProcessorURLEncode:
+		return urlEncode(payload), nil
+	case models.ProcessorDoubleURLEncode:
+		return urlEncode(urlEncode(payload)), nil
+	case models.ProcessorBase64:
+		return base64.StdEncoding.EncodeToString([]byte(payload)), nil
+	case models.ProcessorHex:
+		return hex.EncodeToString([]byte(payload)), nil
+	case models.ProcessorHTMLEntities:
+		return htmlEntities(payload), nil
+	case models.ProcessorHash:
+		return hashPayload(payload, processor.Algorithm)
+	case models.ProcessorPrefix:
+		return processor.Value + payload, nil
+	case models.ProcessorSuffix:
+		return payload + processor.Value, nil
+	case models.ProcessorUppercase:
+		return strings.ToUpper(payload), nil
+	case models.ProcessorLowercase:
+		return strings.ToLower(payload), nil
+	case models.ProcessorJSONEscape:
+		return jsonEscape(payload), nil
+	default:
+		return "", fmt.Errorf("%w %q", ErrUnknownProcessor, processor.Type)
+	}
+}
+
+// urlEncode escapes everything except unreserved characters, so that payload
+// metacharacters such as '/' or '&' never reach the target unencoded.
+func urlEncode(payload string) string {
+	return strings.ReplaceAll(url.QueryEscape(payload), "+", "%20")
+}
+
+func jsonEscape(payload string) string {
+	buffer := &bytes.Buffer{}
+
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(payload)
+
+	escaped := strings.TrimSuffix(buffer.String(), "\n")
+
+	return escaped[1 : len(escaped)-1]
+}
+
+func htmlEntities(payload string) string {
+	builder := &strings.Builder{}
+
+	for _, r := range payload {
+		if r < 0x80 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+
+			continue
+		}
+
+		fmt.Fprintf(builder, "&#x%x;", r)
+	}
+
+	return builder.String()
+}
+
+func hashPayload(payload, algorithm string) (string, error) {
+	var hasher hash.Hash
+
+	switch algorithm {
+	case models.HashMD5:
+		hasher = md5.New()
+	case models.HashSHA1:
+		hasher = sha1.New()
+	case "", models.HashSHA256:
+		hasher = sha256.New()
+	default:
+		return "", fmt.Errorf("%w: hash algorithm %q", ErrUnknownProcessor, algorithm)
+	}
+
+	hasher.Write([]byte(payload))
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,64 @@
+package fuzz
+
+import (
+	"errors"
+	"strings"
+)
+
+const Marker = "§"
+
+var (
+	ErrUnbalancedMarkers = errors.New("template has an unpaired position marker")
+	ErrNoPositions       = errors.New("template has no marked positions")
+)
+
+// Template is a raw HTTP request split around its §marked§ positions.
+type Template struct {
+	fixed    []string
+	defaults []string
+}
+
+func ParseTemplate(text string) (*Template, error) {
+	parts := strings.Split(text, Marker)
+	if len(parts)%2 == 0 {
+		return nil, ErrUnbalancedMarkers
+	}
+
+	if len(parts) == 1 {
+		return nil, ErrNoPositions
+	}
+
+	template := &Template{}
+
+	for i, part := range parts {
+		if i%2 == 0 {
+			template.fixed = append(template.fixed, part)
+		} else {
+			template.defaults = append(template.defaults, part)
+		}
+	}
+
+	return template, nil
+}
+
+func (template *Template) Positions() int {
+	return len(template.defaults)
+}
+
+func (template *Template) Defaults() []string {
+	return template.defaults
+}
+
+func (template *Template) Render(values []string) string {
+	builder := &strings.Builder{}
+
+	for i, fixed := range template.fixed {
+		builder.WriteString(fixed)
+
+		if i < len(values) {
+			builder.WriteString(values[i])
+		}
+	}
+
+	return builder.String()
+}
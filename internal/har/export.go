@@ -0,0 +1,191 @@
+package har
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+func Export(transactions []*models.Transaction) *HAR {
+	entries := make([]*Entry, 0, len(transactions))
+
+	for _, transaction := range transactions {
+		entries = append(entries, NewEntry(transaction.Request, transaction.Response))
+	}
+
+	return &HAR{
+		Log: &Log{
+			Version: Version,
+			Creator: &Creator{Name: creatorName, Version: creatorVersion},
+			Entries: entries,
+		},
+	}
+}
+
+func NewEntry(req *network.HTTPRequest, resp *network.HTTPResponse) *Entry {
+	entry := &Entry{
+		StartedDateTime: req.Time.Format(time.RFC3339Nano),
+		Request:         newRequest(req),
+		Response:        newResponse(resp),
+		Timings:         &Timings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1},
+		Comment:         req.ID,
+	}
+
+	if resp != nil {
+		entry.Time = float64(resp.Duration.Microseconds()) / 1000
+		entry.Timings.Wait = entry.Time
+	}
+
+	return entry
+}
+
+func newRequest(req *network.HTTPRequest) *Request {
+	harReq := &Request{
+		Method:      req.Method,
+		URL:         req.URL(),
+		HTTPVersion: httpVersion(req.Proto),
+		Cookies:     make([]*Cookie, 0, len(req.Cookies)),
+		Headers:     headers(req.Header),
+		QueryString: nameValues(req.GetParams),
+		HeadersSize: -1,
+		BodySize:    len(req.Body),
+	}
+
+	for _, cookie := range req.Cookies {
+		harReq.Cookies = append(harReq.Cookies, &Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+
+	if len(req.PostParams) == 0 && len(req.Body) == 0 {
+		return harReq
+	}
+
+	postData := &PostData{MimeType: req.Header.Get("Content-Type")}
+
+	switch {
+	case len(req.PostParams) > 0 && strings.HasPrefix(postData.MimeType, "application/x-www-form-urlencoded"):
+		postData.Params = make([]*PostParam, 0, len(req.PostParams))
+
+		for _, param := range nameValues(req.PostParams) {
+			postData.Params = append(postData.Params, &PostParam{Name: param.Name, Value: param.Value})
+		}
+
+		harReq.BodySize = len(req.PostParams.Encode())
+	case len(req.PostParams) > 0:
+		postData.Text = req.PostParams.Encode()
+		harReq.BodySize = len(postData.Text)
+	default:
+		postData.Text, postData.Encoding = encodeBody(req.Body)
+	}
+
+	harReq.PostData = postData
+
+	return harReq
+}
+
+func newResponse(resp *network.HTTPResponse) *Response {
+	if resp == nil {
+		return &Response{
+			Cookies:     []*Cookie{},
+			Headers:     []*NameValue{},
+			Content:     &Content{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		}
+	}
+
+	harResp := &Response{
+		Status:      resp.Code,
+		StatusText:  statusText(resp),
+		HTTPVersion: httpVersion(resp.Proto),
+		Cookies:     make([]*Cookie, 0),
+		Headers:     headers(resp.Headers),
+		Content: &Content{
+			Size:     len(resp.Body),
+			MimeType: resp.Headers.Get("Content-Type"),
+		},
+		RedirectURL: resp.Headers.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(resp.Body),
+	}
+
+	harResp.Content.Text, harResp.Content.Encoding = encodeBody(resp.Body)
+
+	if resp.Decoded() {
+		harResp.BodySize = len(resp.WireBody)
+		harResp.Content.Compression = len(resp.Body) - len(resp.WireBody)
+	}
+
+	for _, cookie := range (&http.Response{Header: resp.Headers}).Cookies() {
+		harCookie := &Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			HTTPOnly: cookie.HttpOnly,
+			Secure:   cookie.Secure,
+		}
+
+		if !cookie.Expires.IsZero() {
+			harCookie.Expires = cookie.Expires.Format(time.RFC3339)
+		}
+
+		harResp.Cookies = append(harResp.Cookies, harCookie)
+	}
+
+	return harResp
+}
+
+func statusText(resp *network.HTTPResponse) string {
+	code, text, found := strings.Cut(resp.Message, " ")
+	if found && code == strconv.Itoa(resp.Code) {
+		return text
+	}
+
+	return http.StatusText(resp.Code)
+}
+
+func httpVersion(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+
+	return proto
+}
+
+func headers(header http.Header) []*NameValue {
+	result := make([]*NameValue, 0, len(header))
+
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			result = append(result, &NameValue{Name: key, Value: value})
+		}
+	}
+
+	return result
+}
+
+func nameValues(values url.Values) []*NameValue {
+	return headers(http.Header(values))
+}
+
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
@@ -0,0 +1,104 @@
+package har
+
+const (
+	Version = "1.2"
+
+	creatorName    = "proxy"
+	creatorVersion = "1.0"
+)
+
+type HAR struct {
+	Log *Log `json:"log"`
+}
+
+type Log struct {
+	Version string   `json:"version"`
+	Creator *Creator `json:"creator"`
+	Entries []*Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type Entry struct {
+	StartedDateTime string    `json:"startedDateTime"`
+	Time            float64   `json:"time"`
+	Request         *Request  `json:"request"`
+	Response        *Response `json:"response"`
+	Cache           struct{}  `json:"cache"`
+	Timings         *Timings  `json:"timings"`
+	Comment         string    `json:"comment,omitempty"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Cookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+type PostParam struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+type PostData struct {
+	MimeType string       `json:"mimeType"`
+	Params   []*PostParam `json:"params,omitempty"`
+	Text     string       `json:"text,omitempty"`
+	Encoding string       `json:"encoding,omitempty"`
+}
+
+type Request struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []*Cookie    `json:"cookies"`
+	Headers     []*NameValue `json:"headers"`
+	QueryString []*NameValue `json:"queryString"`
+	PostData    *PostData    `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type Content struct {
+	Size        int    `json:"size"`
+	Compression int    `json:"compression,omitempty"`
+	MimeType    string `json:"mimeType"`
+	Text        string `json:"text,omitempty"`
+	Encoding    string `json:"encoding,omitempty"`
+}
+
+type Response struct {
+	Status      int          `json:"status"`
+	StatusText  string       `json:"statusText"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []*Cookie    `json:"cookies"`
+	Headers     []*NameValue `json:"headers"`
+	Content     *Content     `json:"content"`
+	RedirectURL string       `json:"redirectURL"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type Timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
@@ -0,0 +1,195 @@
+package har
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func roundTrip(t *testing.T, transaction *models.Transaction) *models.Transaction {
+	t.Helper()
+
+	data, err := json.Marshal(Export([]*models.Transaction{transaction}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	archive := &HAR{}
+	if err := json.Unmarshal(data, archive); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	entries := archive.ImportEntries()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+
+	if entries[0].Err != nil {
+		t.Fatalf("import: %v", entries[0].Err)
+	}
+
+	return entries[0].Transaction
+}
+
+func postRequest(t *testing.T, contentType string, body []byte) *network.HTTPRequest {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/upload?q=1", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Trace", "abc")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+
+	return network.NewHTTPRequest(req)
+}
+
+func TestRoundTripRequests(t *testing.T) {
+	multipart := "--X\r\nContent-Disposition: form-data; name=\"file\"; filename=\"a.bin\"\r\n" +
+		"Content-Type: application/octet-stream\r\n\r\n\x00\xff\r\n--X--\r\n"
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		params      url.Values
+	}{
+		{name: "urlencoded", contentType: "application/x-www-form-urlencoded", body: []byte("a=1&b=x+y"),
+			params: url.Values{"a": {"1"}, "b": {"x y"}}},
+		{name: "multipart", contentType: "multipart/form-data; boundary=X", body: []byte(multipart)},
+		{name: "text", contentType: "application/json", body: []byte(`{"a":"b"}`)},
+		{name: "binary", contentType: "application/octet-stream", body: []byte{0x00, 0x01, 0xfe, 0xff}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sent := postRequest(t, test.contentType, test.body)
+			got := roundTrip(t, &models.Transaction{Request: sent}).Request
+
+			if got.Method != sent.Method || got.URL() != sent.URL() {
+				t.Errorf("request = %s %s, want %s %s", got.Method, got.URL(), sent.Method, sent.URL())
+			}
+
+			if got.Header.Get("X-Trace") != "abc" || got.Header.Get("Content-Type") != test.contentType {
+				t.Errorf("headers = %v, want X-Trace and Content-Type kept", got.Header)
+			}
+
+			if len(got.Cookies) != 1 || got.Cookies[0].Name != "session" || got.Cookies[0].Value != "s1" {
+				t.Errorf("cookies = %v, want session=s1", got.Cookies)
+			}
+
+			if test.params != nil {
+				if !reflect.DeepEqual(got.PostParams, test.params) {
+					t.Errorf("post params = %v, want %v", got.PostParams, test.params)
+				}
+
+				return
+			}
+
+			if !bytes.Equal(got.Body, test.body) {
+				t.Errorf("body = %q, want %q", got.Body, test.body)
+			}
+
+			if len(got.Parts) != len(sent.Parts) {
+				t.Errorf("got %d parts, want %d", len(got.Parts), len(sent.Parts))
+			}
+		})
+	}
+}
+
+func TestRoundTripResponses(t *testing.T) {
+	plain := []byte("hello, world")
+
+	buffer := &bytes.Buffer{}
+	writer := gzip.NewWriter(buffer)
+	writer.Write(plain)
+	writer.Close()
+
+	tests := []struct {
+		name     string
+		headers  http.Header
+		wire     []byte
+		body     []byte
+		encoding bool
+	}{
+		{
+			name:    "plain",
+			headers: http.Header{"Content-Type": {"text/plain"}},
+			wire:    plain,
+			body:    plain,
+		},
+		{
+			name: "gzip",
+			headers: http.Header{"Content-Type": {"text/plain"}, "Content-Encoding": {"gzip"},
+				"Content-Length": {strconv.Itoa(buffer.Len())}},
+			wire:     buffer.Bytes(),
+			body:     plain,
+			encoding: true,
+		},
+		{
+			name:    "binary",
+			headers: http.Header{"Content-Type": {"image/png"}},
+			wire:    []byte{0x89, 'P', 'N', 'G', 0x00, 0xff},
+			body:    []byte{0x89, 'P', 'N', 'G', 0x00, 0xff},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sent := &network.HTTPResponse{
+				Code:     http.StatusOK,
+				Message:  "200 OK",
+				Proto:    "HTTP/1.1",
+				Headers:  test.headers,
+				Duration: 150 * time.Millisecond,
+			}
+			sent.SetWireBody(test.wire)
+
+			request := postRequest(t, "text/plain", nil)
+			got := roundTrip(t, &models.Transaction{Request: request, Response: sent}).Response
+
+			if got == nil {
+				t.Fatal("response was not imported")
+			}
+
+			if got.Code != sent.Code || got.Message != sent.Message || got.Duration != sent.Duration {
+				t.Errorf("response = %d %q %v, want %d %q %v", got.Code, got.Message, got.Duration,
+					sent.Code, sent.Message, sent.Duration)
+			}
+
+			if !bytes.Equal(got.Body, test.body) {
+				t.Errorf("body = %q, want %q", got.Body, test.body)
+			}
+
+			if got.Headers.Get("Content-Type") != test.headers.Get("Content-Type") {
+				t.Errorf("Content-Type = %q, want %q", got.Headers.Get("Content-Type"), test.headers.Get("Content-Type"))
+			}
+
+			if !test.encoding {
+				return
+			}
+
+			if encoding := got.Headers.Get("Content-Encoding"); encoding != "" {
+				t.Errorf("Content-Encoding = %q, want it dropped", encoding)
+			}
+
+			if length := got.Headers.Get("Content-Length"); length != strconv.Itoa(len(test.body)) {
+				t.Errorf("Content-Length = %q, want %d", length, len(test.body))
+			}
+
+			if got.DecodeError != "" {
+				t.Errorf("DecodeError = %q, want none", got.DecodeError)
+			}
+		})
+	}
+}
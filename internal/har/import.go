@@ -0,0 +1,204 @@
+package har
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoRequest = errors.New("entry has no request")
+	ErrBadURL    = errors.New("entry has invalid request url")
+)
+
+func (entry *Entry) Transaction() (*models.Transaction, error) {
+	if entry.Request == nil {
+		return nil, ErrNoRequest
+	}
+
+	request, err := entry.Request.toNetwork()
+	if err != nil {
+		return nil, err
+	}
+
+	if startedAt, err := time.Parse(time.RFC3339Nano, entry.StartedDateTime); err == nil {
+		request.Time = startedAt
+	}
+
+	transaction := &models.Transaction{Request: request}
+
+	if entry.Response == nil || entry.Response.Status == 0 {
+		return transaction, nil
+	}
+
+	transaction.Response, err = entry.Response.toNetwork()
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Time > 0 {
+		transaction.Response.Duration = time.Duration(entry.Time * float64(time.Millisecond))
+	}
+
+	return transaction, nil
+}
+
+func (harReq *Request) toNetwork() (*network.HTTPRequest, error) {
+	requestURL, err := url.Parse(harReq.URL)
+	if err != nil || requestURL.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrBadURL, harReq.URL)
+	}
+
+	if requestURL.Scheme == "" {
+		requestURL.Scheme = "http"
+	}
+
+	body, err := harReq.body()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(harReq.Method, requestURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Proto = httpVersion(harReq.HTTPVersion)
+
+	for _, header := range harReq.Headers {
+		if strings.HasPrefix(header.Name, ":") {
+			continue
+		}
+
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	if req.Header.Get("Cookie") == "" {
+		for _, cookie := range harReq.Cookies {
+			req.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+		}
+	}
+
+	if harReq.PostData != nil && req.Header.Get("Content-Type") == "" && harReq.PostData.MimeType != "" {
+		req.Header.Set("Content-Type", harReq.PostData.MimeType)
+	}
+
+	return network.NewHTTPRequest(req), nil
+}
+
+func (harReq *Request) body() ([]byte, error) {
+	postData := harReq.PostData
+	if postData == nil {
+		return nil, nil
+	}
+
+	if postData.Text != "" {
+		return decodeBody(postData.Text, postData.Encoding)
+	}
+
+	if boundary, ok := network.MultipartBoundary(http.Header{"Content-Type": {postData.MimeType}}); ok {
+		parts := make([]*network.MultipartPart, 0, len(postData.Params))
+
+		for _, param := range postData.Params {
+			parts = append(parts, &network.MultipartPart{
+				Name:        param.Name,
+				Filename:    param.FileName,
+				ContentType: param.ContentType,
+				Content:     []byte(param.Value),
+			})
+		}
+
+		return network.EncodeMultipart(parts, boundary)
+	}
+
+	values := url.Values{}
+
+	for _, param := range postData.Params {
+		values.Add(param.Name, param.Value)
+	}
+
+	return []byte(values.Encode()), nil
+}
+
+func (harResp *Response) toNetwork() (*network.HTTPResponse, error) {
+	body := []byte(nil)
+
+	if harResp.Content != nil {
+		var err error
+
+		body, err = decodeBody(harResp.Content.Text, harResp.Content.Encoding)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	headers := http.Header{}
+
+	for _, header := range harResp.Headers {
+		if strings.HasPrefix(header.Name, ":") {
+			continue
+		}
+
+		headers.Add(header.Name, header.Value)
+	}
+
+	// HAR keeps the decoded content, so the codings it was sent with no longer apply.
+	if len(network.ContentEncodings(headers)) > 0 {
+		headers.Del("Content-Encoding")
+
+		if headers.Get("Content-Length") != "" {
+			headers.Set("Content-Length", strconv.Itoa(len(body)))
+		}
+	}
+
+	statusText := harResp.StatusText
+	if statusText == "" {
+		statusText = http.StatusText(harResp.Status)
+	}
+
+	return &network.HTTPResponse{
+		Code:    harResp.Status,
+		Message: fmt.Sprintf("%d %s", harResp.Status, statusText),
+		Proto:   httpVersion(harResp.HTTPVersion),
+		Headers: headers,
+		Body:    body,
+	}, nil
+}
+
+func decodeBody(text, encoding string) ([]byte, error) {
+	if encoding != "base64" {
+		return []byte(text), nil
+	}
+
+	body, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 body: %w", err)
+	}
+
+	return body, nil
+}
+
+func (har *HAR) ImportEntries() []*models.ImportEntry {
+	entries := make([]*models.ImportEntry, 0, len(har.Log.Entries))
+
+	for _, entry := range har.Log.Entries {
+		if entry == nil {
+			entries = append(entries, &models.ImportEntry{Err: ErrNoRequest})
+
+			continue
+		}
+
+		transaction, err := entry.Transaction()
+		entries = append(entries, &models.ImportEntry{Transaction: transaction, Err: err})
+	}
+
+	return entries
+}
@@ -0,0 +1,230 @@
+package insertion
+
+import (
+	"errors"
+	"net/url"
+	"proxy/internal/network"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	LocationQuery     = "query"
+	LocationBody      = "body"
+	LocationJSON      = "json"
+	LocationMultipart = "multipart"
+	LocationFilename  = "filename"
+	LocationXML       = "xml"
+)
+
+var (
+	ErrBadPoint    = errors.New("invalid insertion point")
+	ErrNoSuchPoint = errors.New("request has no such insertion point")
+	ErrBodyRebuild = errors.New("body can not be rebuilt")
+)
+
+var locations = map[string]bool{
+	LocationQuery: true, LocationBody: true, LocationJSON: true,
+	LocationMultipart: true, LocationFilename: true, LocationXML: true,
+}
+
+// Point is a place in a request where a value can be inserted.
+type Point struct {
+	Location string `json:"location"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+}
+
+func (point *Point) String() string {
+	return point.Location + ":" + point.Name
+}
+
+func ParsePoint(text string) (*Point, error) {
+	location, name, ok := strings.Cut(text, ":")
+	if !ok || name == "" || !locations[location] {
+		return nil, ErrBadPoint
+	}
+
+	return &Point{Location: location, Name: name}, nil
+}
+
+func Points(request *network.HTTPRequest) []*Point {
+	points := make([]*Point, 0)
+
+	points = append(points, valuesPoints(LocationQuery, request.GetParams)...)
+	points = append(points, valuesPoints(LocationBody, request.PostParams)...)
+
+	if document, ok := jsonBody(request); ok {
+		walkJSON(document, "", func(pointer, value string) {
+			points = append(points, &Point{Location: LocationJSON, Name: pointer, Value: value})
+		})
+
+		return points
+	}
+
+	if parts, err := network.ParseMultipart(request.Header, request.DecodedBody()); err == nil {
+		for i, part := range parts {
+			name := partName(parts, i)
+			points = append(points, &Point{Location: LocationMultipart, Name: name, Value: string(part.Content)})
+
+			if part.Filename != "" {
+				points = append(points, &Point{Location: LocationFilename, Name: name, Value: part.Filename})
+			}
+		}
+
+		return points
+	}
+
+	if len(request.PostParams) == 0 && IsXML(request) {
+		doc, err := ParseXML(request.DecodedBody())
+		if err != nil {
+			return points
+		}
+
+		for _, node := range doc.Nodes() {
+			points = append(points, &Point{Location: LocationXML, Name: node.Path, Value: node.Value})
+		}
+	}
+
+	return points
+}
+
+func Find(request *network.HTTPRequest, point *Point) (*Point, error) {
+	for _, candidate := range Points(request) {
+		if candidate.Location == point.Location && candidate.Name == point.Name {
+			return candidate, nil
+		}
+	}
+
+	return nil, ErrNoSuchPoint
+}
+
+func valuesPoints(location string, values url.Values) []*Point {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	points := make([]*Point, 0, len(names))
+
+	for _, name := range names {
+		value := ""
+		if len(values[name]) > 0 {
+			value = values[name][0]
+		}
+
+		points = append(points, &Point{Location: location, Name: name, Value: value})
+	}
+
+	return points
+}
+
+func partName(parts []*network.MultipartPart, index int) string {
+	count := 0
+
+	for _, part := range parts[:index+1] {
+		if part.Name == parts[index].Name {
+			count++
+		}
+	}
+
+	if count == 1 {
+		return parts[index].Name
+	}
+
+	return parts[index].Name + "[" + strconv.Itoa(count) + "]"
+}
+
+// Inject returns a copy of request with value at point, re-encoding JSON, multipart and XML bodies.
+func Inject(request *network.HTTPRequest, point *Point, value string) (*network.HTTPRequest, error) {
+	injected := request.Clone()
+	injected.ID = ""
+	injected.ParentID = ""
+
+	var err error
+
+	switch point.Location {
+	case LocationQuery:
+		if injected.GetParams == nil {
+			injected.GetParams = url.Values{}
+		}
+
+		injected.GetParams.Set(point.Name, value)
+	case LocationBody:
+		if _, ok := injected.PostParams[point.Name]; !ok {
+			return nil, ErrNoSuchPoint
+		}
+
+		injected.PostParams.Set(point.Name, value)
+	case LocationJSON:
+		err = injectJSON(injected, point.Name, value)
+	case LocationMultipart, LocationFilename:
+		err = injectMultipart(injected, point, value)
+	case LocationXML:
+		err = injectXML(injected, point.Name, value)
+	default:
+		err = ErrBadPoint
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return injected, nil
+}
+
+func setBody(request *network.HTTPRequest, body []byte) {
+	request.Body = body
+	request.Header.Del("Content-Encoding")
+
+	if request.Header.Get("Content-Length") != "" {
+		request.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+}
+
+func injectMultipart(request *network.HTTPRequest, point *Point, value string) error {
+	parts, err := network.ParseMultipart(request.Header, request.DecodedBody())
+	if err != nil {
+		return ErrNoSuchPoint
+	}
+
+	for i, part := range parts {
+		if partName(parts, i) != point.Name {
+			continue
+		}
+
+		if point.Location == LocationFilename {
+			part.Filename = value
+		} else {
+			part.Content = []byte(value)
+		}
+
+		err = request.SetParts(parts)
+		if err != nil {
+			return errors.Join(ErrBodyRebuild, err)
+		}
+
+		return nil
+	}
+
+	return ErrNoSuchPoint
+}
+
+func injectXML(request *network.HTTPRequest, path, value string) error {
+	doc, err := ParseXML(request.DecodedBody())
+	if err != nil {
+		return ErrNoSuchPoint
+	}
+
+	node, ok := doc.Node(path)
+	if !ok {
+		return ErrNoSuchPoint
+	}
+
+	setBody(request, doc.Set(node, value))
+
+	return nil
+}
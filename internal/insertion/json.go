@@ -0,0 +1,122 @@
+package insertion
+
+import (
+	"bytes"
+	"encoding/json"
+	"proxy/internal/network"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func jsonBody(request *network.HTTPRequest) (any, bool) {
+	if len(request.PostParams) > 0 || !strings.Contains(strings.ToLower(request.Header.Get("Content-Type")), "json") {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(request.DecodedBody()))
+	decoder.UseNumber()
+
+	var document any
+
+	err := decoder.Decode(&document)
+	if err != nil {
+		return nil, false
+	}
+
+	return document, true
+}
+
+func walkJSON(value any, pointer string, visit func(pointer, value string)) {
+	switch typed := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			walkJSON(typed[key], pointer+"/"+escapePointer(key), visit)
+		}
+	case []any:
+		for i, item := range typed {
+			walkJSON(item, pointer+"/"+strconv.Itoa(i), visit)
+		}
+	case string:
+		visit(pointer, typed)
+	case json.Number:
+		visit(pointer, typed.String())
+	case bool:
+		visit(pointer, strconv.FormatBool(typed))
+	case nil:
+		visit(pointer, "null")
+	}
+}
+
+func injectJSON(request *network.HTTPRequest, pointer, value string) error {
+	document, ok := jsonBody(request)
+	if !ok || !strings.HasPrefix(pointer, "/") {
+		return ErrNoSuchPoint
+	}
+
+	document, ok = setJSON(document, strings.Split(pointer, "/")[1:], value)
+	if !ok {
+		return ErrNoSuchPoint
+	}
+
+	// json.Marshal would escape <, > and & in payloads.
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	err := encoder.Encode(document)
+	if err != nil {
+		return ErrBodyRebuild
+	}
+
+	setBody(request, bytes.TrimSuffix(buffer.Bytes(), []byte("\n")))
+
+	return nil
+}
+
+func setJSON(document any, path []string, value string) (any, bool) {
+	if len(path) == 0 {
+		return value, true
+	}
+
+	switch typed := document.(type) {
+	case map[string]any:
+		key := unescapePointer(path[0])
+
+		child, ok := typed[key]
+		if !ok {
+			return document, false
+		}
+
+		typed[key], ok = setJSON(child, path[1:], value)
+
+		return document, ok
+	case []any:
+		index, err := strconv.Atoi(path[0])
+		if err != nil || index < 0 || index >= len(typed) {
+			return document, false
+		}
+
+		var ok bool
+		typed[index], ok = setJSON(typed[index], path[1:], value)
+
+		return document, ok
+	default:
+		return document, false
+	}
+}
+
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func unescapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~1", "/"), "~0", "~")
+}
@@ -0,0 +1,249 @@
+package insertion
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"proxy/internal/network"
+	"strconv"
+	"strings"
+)
+
+var ErrNotXML = errors.New("body is not an XML document")
+
+// xml.EscapeText would also escape newlines and tabs.
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+type XMLDocument struct {
+	tokens []xml.Token
+	root   string
+	nodes  []*XMLNode
+}
+
+// XMLNode is a text-only element at Path, or an attribute at Path/@name.
+type XMLNode struct {
+	Path  string
+	Value string
+	start int
+	attr  int
+}
+
+func (node *XMLNode) IsAttr() bool {
+	return node.attr >= 0
+}
+
+func IsXML(req *network.HTTPRequest) bool {
+	mediaType := strings.ToLower(req.Header.Get("Content-Type"))
+	if strings.Contains(mediaType, "xml") {
+		return true
+	}
+
+	return bytes.HasPrefix(bytes.TrimSpace(req.DecodedBody()), []byte("<"))
+}
+
+func ParseXML(body []byte) (*XMLDocument, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = true
+
+	doc := &XMLDocument{}
+	path := make([]string, 0)
+	siblings := []map[string]int{{}}
+
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Join(ErrNotXML, err)
+		}
+
+		token = xml.CopyToken(token)
+		doc.tokens = append(doc.tokens, token)
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			name := qualifiedName(element.Name)
+			if doc.root == "" {
+				doc.root = name
+			}
+
+			siblings[len(siblings)-1][name]++
+			if count := siblings[len(siblings)-1][name]; count > 1 {
+				name += "[" + strconv.Itoa(count) + "]"
+			}
+
+			path = append(path, name)
+			siblings = append(siblings, map[string]int{})
+
+			elementPath := "/" + strings.Join(path, "/")
+			for i, attr := range element.Attr {
+				doc.nodes = append(doc.nodes, &XMLNode{
+					Path:  elementPath + "/@" + qualifiedName(attr.Name),
+					Value: attr.Value,
+					start: len(doc.tokens) - 1,
+					attr:  i,
+				})
+			}
+		case xml.EndElement:
+			if len(path) == 0 {
+				return nil, ErrNotXML
+			}
+
+			doc.addLeaf("/" + strings.Join(path, "/"))
+
+			path = path[:len(path)-1]
+			siblings = siblings[:len(siblings)-1]
+		}
+	}
+
+	if doc.root == "" || len(path) > 0 {
+		return nil, ErrNotXML
+	}
+
+	return doc, nil
+}
+
+func (doc *XMLDocument) addLeaf(path string) {
+	end := len(doc.tokens) - 1
+	start := end - 1
+	value := ""
+
+	if text, ok := doc.tokens[start].(xml.CharData); ok {
+		value = string(text)
+		start--
+	}
+
+	if start < 0 {
+		return
+	}
+
+	if _, ok := doc.tokens[start].(xml.StartElement); !ok {
+		return
+	}
+
+	doc.nodes = append(doc.nodes, &XMLNode{Path: path, Value: value, start: start, attr: -1})
+}
+
+func (doc *XMLDocument) Root() string {
+	return doc.root
+}
+
+func (doc *XMLDocument) Nodes() []*XMLNode {
+	return doc.nodes
+}
+
+func (doc *XMLDocument) Leaves() []*XMLNode {
+	leaves := make([]*XMLNode, 0)
+
+	for _, node := range doc.nodes {
+		if !node.IsAttr() {
+			leaves = append(leaves, node)
+		}
+	}
+
+	return leaves
+}
+
+func (doc *XMLDocument) Node(path string) (*XMLNode, bool) {
+	for _, node := range doc.nodes {
+		if node.Path == path {
+			return node, true
+		}
+	}
+
+	return nil, false
+}
+
+func (doc *XMLDocument) Set(node *XMLNode, value string) []byte {
+	if node.IsAttr() {
+		return doc.Render("", node, attrEscaper.Replace(value))
+	}
+
+	return doc.Render("", node, textEscaper.Replace(value))
+}
+
+// Render writes the document with markup unescaped as the content of node and doctype, when
+// not empty, in place of the document's own DOCTYPE.
+func (doc *XMLDocument) Render(doctype string, node *XMLNode, markup string) []byte {
+	buffer := &bytes.Buffer{}
+	written := doctype == ""
+
+	for i := 0; i < len(doc.tokens); i++ {
+		switch token := doc.tokens[i].(type) {
+		case xml.ProcInst:
+			buffer.WriteString("<?" + token.Target)
+			if len(token.Inst) > 0 {
+				buffer.WriteString(" " + string(token.Inst))
+			}
+			buffer.WriteString("?>")
+		case xml.Directive:
+			if doctype == "" || !strings.HasPrefix(strings.ToUpper(string(token)), "DOCTYPE") {
+				buffer.WriteString("<!" + string(token) + ">")
+			}
+		case xml.Comment:
+			buffer.WriteString("<!--" + string(token) + "-->")
+		case xml.CharData:
+			textEscaper.WriteString(buffer, string(token))
+		case xml.EndElement:
+			buffer.WriteString("</" + qualifiedName(token.Name) + ">")
+		case xml.StartElement:
+			if !written {
+				buffer.WriteString(doctype)
+				written = true
+			}
+
+			if node == nil || node.start != i {
+				writeStart(buffer, token, -1, "")
+
+				continue
+			}
+
+			if node.IsAttr() {
+				writeStart(buffer, token, node.attr, markup)
+
+				continue
+			}
+
+			writeStart(buffer, token, -1, "")
+			buffer.WriteString(markup)
+
+			if _, ok := doc.tokens[i+1].(xml.CharData); ok {
+				i++
+			}
+		}
+	}
+
+	return buffer.Bytes()
+}
+
+func writeStart(buffer *bytes.Buffer, element xml.StartElement, replaced int, markup string) {
+	buffer.WriteString("<" + qualifiedName(element.Name))
+
+	for i, attr := range element.Attr {
+		buffer.WriteString(" " + qualifiedName(attr.Name) + `="`)
+
+		if i == replaced {
+			buffer.WriteString(markup)
+		} else {
+			attrEscaper.WriteString(buffer, attr.Value)
+		}
+
+		buffer.WriteString(`"`)
+	}
+
+	buffer.WriteString(">")
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
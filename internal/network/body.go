@@ -0,0 +1,146 @@
+package network
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+const (
+	BodyText   = "text"
+	BodyBase64 = "base64"
+)
+
+var (
+	ErrBadBodyEncoding = errors.New("invalid body encoding")
+	ErrUnknownCharset  = errors.New("unknown charset")
+
+	metaCharset = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([\w.:-]+)`)
+	xmlEncoding = regexp.MustCompile(`^\s*<\?xml[^>]+encoding\s*=\s*["']([\w.:-]+)["']`)
+)
+
+// DetectMIME returns the declared media type, or the sniffed one when it is missing or generic.
+func DetectMIME(header http.Header, body []byte) string {
+	declared := ""
+
+	if mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type")); err == nil {
+		declared = strings.ToLower(mediaType)
+	}
+
+	if declared != "" && declared != "application/octet-stream" && declared != "text/plain" {
+		return declared
+	}
+
+	if len(body) == 0 {
+		return declared
+	}
+
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(body))
+
+	if sniffed == "text/plain" {
+		trimmed := bytes.TrimSpace(body)
+		if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+			return "application/json"
+		}
+	}
+
+	if declared != "" && sniffed == "application/octet-stream" {
+		return declared
+	}
+
+	return sniffed
+}
+
+func IsText(mediaType string) bool {
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+
+	for _, suffix := range []string{"json", "xml", "javascript", "ecmascript", "x-www-form-urlencoded", "graphql"} {
+		if strings.HasSuffix(mediaType, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Charset looks in Content-Type, then in an XML declaration or HTML meta tag.
+func Charset(header http.Header, body []byte) string {
+	_, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err == nil && params["charset"] != "" {
+		return strings.ToLower(params["charset"])
+	}
+
+	head := body
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+
+	if match := xmlEncoding.FindSubmatch(head); match != nil {
+		return strings.ToLower(string(match[1]))
+	}
+
+	if match := metaCharset.FindSubmatch(head); match != nil {
+		return strings.ToLower(string(match[1]))
+	}
+
+	return ""
+}
+
+func ToUTF8(body []byte, charset string) ([]byte, error) {
+	if charset == "" || strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "utf8") {
+		return body, nil
+	}
+
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return body, fmt.Errorf("%w: %s", ErrUnknownCharset, charset)
+	}
+
+	converted, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return body, err
+	}
+
+	return converted, nil
+}
+
+// EncodeJSONBody keeps valid UTF-8 as text and writes anything else in base64.
+func EncodeJSONBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), BodyText
+	}
+
+	return base64.StdEncoding.EncodeToString(body), BodyBase64
+}
+
+// DecodeJSONBody uses fallback for bodies stored without an encoding.
+func DecodeJSONBody(text, encoding, fallback string) ([]byte, error) {
+	if encoding == "" {
+		encoding = fallback
+	}
+
+	switch encoding {
+	case BodyText:
+		return []byte(text), nil
+	case BodyBase64:
+		body, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrBadBodyEncoding, err)
+		}
+
+		return body, nil
+	default:
+		return nil, ErrBadBodyEncoding
+	}
+}
@@ -0,0 +1,146 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// MaxDecodedSize bounds a decoded body, so that a small compressed body can not exhaust memory.
+const MaxDecodedSize = 64 << 20
+
+var (
+	ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+	ErrDecodeBody          = errors.New("body can not be decoded")
+	ErrDecodedTooLarge     = errors.New("decoded body is too large")
+)
+
+// Decoder wraps a reader of encoded content into a reader of the decoded content.
+type Decoder func(reader io.Reader) (io.ReadCloser, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{
+		"gzip":    decodeGzip,
+		"x-gzip":  decodeGzip,
+		"deflate": decodeDeflate,
+		"br":      decodeBrotli,
+		"zstd":    decodeZstd,
+	}
+)
+
+// RegisterDecoder adds or replaces the decoder of a Content-Encoding token.
+func RegisterDecoder(encoding string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	decoders[strings.ToLower(encoding)] = decoder
+}
+
+func decoderFor(encoding string) (Decoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	decoder, ok := decoders[encoding]
+
+	return decoder, ok
+}
+
+// ContentEncodings lists the codings of header in the order they were applied, leaving out identity.
+func ContentEncodings(header http.Header) []string {
+	encodings := make([]string, 0)
+
+	for _, value := range header.Values("Content-Encoding") {
+		for _, encoding := range strings.Split(value, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if encoding != "" && encoding != "identity" {
+				encodings = append(encodings, encoding)
+			}
+		}
+	}
+
+	return encodings
+}
+
+// DecodeBody removes the content codings listed in header from body, last applied first. When
+// one of them can not be removed the body is returned as it was, together with the error.
+func DecodeBody(header http.Header, body []byte) ([]byte, error) {
+	encodings := ContentEncodings(header)
+	decoded := body
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		decoder, ok := decoderFor(encodings[i])
+		if !ok {
+			return body, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encodings[i])
+		}
+
+		var err error
+
+		decoded, err = decode(decoder, decoded)
+		if err != nil {
+			return body, fmt.Errorf("%w: %s: %w", ErrDecodeBody, encodings[i], err)
+		}
+	}
+
+	return decoded, nil
+}
+
+func decode(decoder Decoder, body []byte) ([]byte, error) {
+	reader, err := decoder(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	decoded, err := io.ReadAll(io.LimitReader(reader, MaxDecodedSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) > MaxDecodedSize {
+		return nil, ErrDecodedTooLarge
+	}
+
+	return decoded, nil
+}
+
+func decodeGzip(reader io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(reader)
+}
+
+// decodeDeflate accepts the zlib stream RFC 9110 asks for as well as the raw deflate stream
+// some servers send instead.
+func decodeDeflate(reader io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(reader)
+
+	header, err := buffered.Peek(2)
+	if err == nil && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 && header[0]&0x0f == 8 {
+		return zlib.NewReader(buffered)
+	}
+
+	return flate.NewReader(buffered), nil
+}
+
+func decodeBrotli(reader io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(reader)), nil
+}
+
+func decodeZstd(reader io.Reader) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(reader, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(MaxDecodedSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return decoder.IOReadCloser(), nil
+}
@@ -0,0 +1,201 @@
+package network
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+func compress(t *testing.T, encoding string, body []byte) []byte {
+	t.Helper()
+
+	buffer := &bytes.Buffer{}
+
+	var writer io.WriteCloser
+
+	switch encoding {
+	case "gzip", "x-gzip":
+		writer = gzip.NewWriter(buffer)
+	case "deflate":
+		writer = zlib.NewWriter(buffer)
+	case "raw-deflate":
+		writer, _ = flate.NewWriter(buffer, flate.DefaultCompression)
+	case "br":
+		writer = brotli.NewWriter(buffer)
+	case "zstd":
+		encoder, err := zstd.NewWriter(buffer)
+		if err != nil {
+			t.Fatalf("zstd.NewWriter: %v", err)
+		}
+
+		writer = encoder
+	default:
+		t.Fatalf("unknown encoding %q", encoding)
+	}
+
+	if _, err := writer.Write(body); err != nil {
+		t.Fatalf("compress %s: %v", encoding, err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("compress %s: %v", encoding, err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestDecodeBody(t *testing.T) {
+	body := []byte(strings.Repeat("hello, world\n", 100))
+
+	tests := []struct {
+		name     string
+		encoding string
+		wire     []byte
+		want     []byte
+		err      error
+	}{
+		{name: "identity", encoding: "identity", wire: body, want: body},
+		{name: "gzip", encoding: "gzip", wire: compress(t, "gzip", body), want: body},
+		{name: "x-gzip", encoding: "x-gzip", wire: compress(t, "x-gzip", body), want: body},
+		{name: "deflate", encoding: "deflate", wire: compress(t, "deflate", body), want: body},
+		{name: "raw deflate", encoding: "deflate", wire: compress(t, "raw-deflate", body), want: body},
+		{name: "br", encoding: "br", wire: compress(t, "br", body), want: body},
+		{name: "zstd", encoding: "zstd", wire: compress(t, "zstd", body), want: body},
+		{name: "upper case", encoding: "GZIP", wire: compress(t, "gzip", body), want: body},
+		{
+			name:     "stacked",
+			encoding: "gzip, br",
+			wire:     compress(t, "br", compress(t, "gzip", body)),
+			want:     body,
+		},
+		{
+			name:     "stacked with identity",
+			encoding: "zstd, identity, deflate",
+			wire:     compress(t, "deflate", compress(t, "zstd", body)),
+			want:     body,
+		},
+		{name: "unknown", encoding: "compress", wire: body, want: body, err: ErrUnsupportedEncoding},
+		{
+			name:     "unknown below known",
+			encoding: "snappy, gzip",
+			wire:     compress(t, "gzip", body),
+			want:     compress(t, "gzip", body),
+			err:      ErrUnsupportedEncoding,
+		},
+		{name: "corrupt", encoding: "gzip", wire: body, want: body, err: ErrDecodeBody},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			header := http.Header{"Content-Encoding": {test.encoding}}
+
+			got, err := DecodeBody(header, test.wire)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("DecodeBody() error = %v, want %v", err, test.err)
+			}
+
+			if !bytes.Equal(got, test.want) {
+				t.Errorf("DecodeBody() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeBodyTooLarge(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writer := gzip.NewWriter(buffer)
+	chunk := make([]byte, 1<<20)
+
+	for written := 0; written <= MaxDecodedSize; written += len(chunk) {
+		if _, err := writer.Write(chunk); err != nil {
+			t.Fatalf("compress: %v", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("compress: %v", err)
+	}
+
+	wire := buffer.Bytes()
+
+	got, err := DecodeBody(http.Header{"Content-Encoding": {"gzip"}}, wire)
+	if !errors.Is(err, ErrDecodedTooLarge) {
+		t.Fatalf("DecodeBody() error = %v, want %v", err, ErrDecodedTooLarge)
+	}
+
+	if !bytes.Equal(got, wire) {
+		t.Errorf("DecodeBody() returned %d bytes, want the %d wire bytes", len(got), len(wire))
+	}
+}
+
+func TestSetWireBody(t *testing.T) {
+	body := []byte("hello, world")
+	wire := compress(t, "gzip", body)
+
+	tests := []struct {
+		name        string
+		encoding    string
+		wire        []byte
+		body        []byte
+		wireBody    []byte
+		decodeError bool
+	}{
+		{name: "plain", wire: body, body: body},
+		{name: "decoded", encoding: "gzip", wire: wire, body: body, wireBody: wire},
+		{name: "unknown", encoding: "compress", wire: wire, body: wire, decodeError: true},
+		{name: "corrupt", encoding: "br", wire: body, body: body, decodeError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := &HTTPResponse{Headers: http.Header{}, WireBody: []byte("stale"), DecodeError: "stale"}
+			if test.encoding != "" {
+				response.Headers.Set("Content-Encoding", test.encoding)
+			}
+
+			response.SetWireBody(test.wire)
+
+			if !bytes.Equal(response.Body, test.body) {
+				t.Errorf("Body = %q, want %q", response.Body, test.body)
+			}
+
+			if !bytes.Equal(response.WireBody, test.wireBody) {
+				t.Errorf("WireBody = %q, want %q", response.WireBody, test.wireBody)
+			}
+
+			if (response.DecodeError != "") != test.decodeError {
+				t.Errorf("DecodeError = %q, want set: %v", response.DecodeError, test.decodeError)
+			}
+		})
+	}
+}
+
+func TestNewHTTPResponseKeepsUndecodableBody(t *testing.T) {
+	wire := []byte("not gzip")
+
+	response, err := NewHTTPResponse(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Encoding": {"gzip"}},
+		Body:       io.NopCloser(bytes.NewReader(wire)),
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPResponse: %v", err)
+	}
+
+	if !strings.Contains(response.DecodeError, ErrDecodeBody.Error()) {
+		t.Errorf("DecodeError = %q, want it to mention %q", response.DecodeError, ErrDecodeBody)
+	}
+
+	if !bytes.Equal(response.Body, wire) {
+		t.Errorf("Body = %q, want the wire bytes %q", response.Body, wire)
+	}
+}
@@ -0,0 +1,150 @@
+package network
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"slices"
+	"strings"
+)
+
+var ErrNotMultipart = errors.New("body is not multipart/form-data")
+
+// MultipartPart is one part of a multipart/form-data body. The filename is kept exactly as
+// sent, without the path stripping mime/multipart applies.
+type MultipartPart struct {
+	Name        string               `json:"name"`
+	Filename    string               `json:"filename,omitempty"`
+	ContentType string               `json:"content_type,omitempty"`
+	Header      textproto.MIMEHeader `json:"headers,omitempty"`
+	Size        int                  `json:"size"`
+	Content     []byte               `json:"content"`
+}
+
+func (part *MultipartPart) Clone() *MultipartPart {
+	clone := *part
+	clone.Content = slices.Clone(part.Content)
+	clone.Header = textproto.MIMEHeader(http.Header(part.Header).Clone())
+
+	return &clone
+}
+
+func MultipartBoundary(header http.Header) (string, bool) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || !strings.EqualFold(mediaType, "multipart/form-data") || params["boundary"] == "" {
+		return "", false
+	}
+
+	return params["boundary"], true
+}
+
+func ParseMultipart(header http.Header, body []byte) ([]*MultipartPart, error) {
+	boundary, ok := MultipartBoundary(header)
+	if !ok {
+		return nil, ErrNotMultipart
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	parts := make([]*MultipartPart, 0)
+
+	for {
+		part, err := reader.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			return parts, nil
+		}
+
+		if err != nil {
+			return nil, errors.Join(ErrNotMultipart, err)
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, errors.Join(ErrNotMultipart, err)
+		}
+
+		parsed := &MultipartPart{
+			ContentType: part.Header.Get("Content-Type"),
+			Header:      part.Header,
+			Size:        len(content),
+			Content:     content,
+		}
+
+		_, params, err := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+		if err == nil {
+			parsed.Name = params["name"]
+			parsed.Filename = params["filename"]
+		}
+
+		parts = append(parts, parsed)
+	}
+}
+
+// EncodeMultipart writes parts with the given boundary. Content-Disposition and Content-Type
+// are rebuilt from the part fields, other part headers are kept.
+func EncodeMultipart(parts []*MultipartPart, boundary string) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	writer := multipart.NewWriter(buffer)
+
+	err := writer.SetBoundary(boundary)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, part := range parts {
+		header := textproto.MIMEHeader{}
+		for name, values := range part.Header {
+			header[name] = append([]string(nil), values...)
+		}
+
+		header.Set("Content-Disposition", contentDisposition(part))
+		header.Del("Content-Type")
+
+		if part.ContentType != "" {
+			header.Set("Content-Type", part.ContentType)
+		}
+
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = partWriter.Write(part.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// contentDisposition quotes the values by hand when mime.FormatMediaType refuses them, so that
+// payloads with control characters still reach the server.
+func contentDisposition(part *MultipartPart) string {
+	params := map[string]string{"name": part.Name}
+	if part.Filename != "" {
+		params["filename"] = part.Filename
+	}
+
+	disposition := mime.FormatMediaType("form-data", params)
+	if disposition != "" {
+		return disposition
+	}
+
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace
+
+	disposition = `form-data; name="` + quote(part.Name) + `"`
+	if part.Filename != "" {
+		disposition += `; filename="` + quote(part.Filename) + `"`
+	}
+
+	return disposition
+}
@@ -1,29 +1,88 @@
 package network
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
+var ErrNoHost = errors.New("request has no host")
+
 type HTTPRequest struct {
-	ID         string         `json:"id"`
-	Proto      string         `json:"proto"`
-	Method     string         `json:"method"`
-	Scheme     string         `json:"scheme"`
-	Host       string         `json:"host"`
-	Port       string         `json:"port"`
-	Path       string         `json:"path"`
-	Header     http.Header    `json:"headers"`
-	PostParams url.Values     `json:"post_params"`
-	GetParams  url.Values     `json:"get_params"`
-	Cookies    []*http.Cookie `json:"cookies"`
-	Body       []byte         `json:"body"`
+	ID         string           `json:"id"`
+	ParentID   string           `json:"parent_id,omitempty"`
+	Proto      string           `json:"proto"`
+	Method     string           `json:"method"`
+	Scheme     string           `json:"scheme"`
+	Host       string           `json:"host"`
+	Port       string           `json:"port"`
+	Path       string           `json:"path"`
+	Header     http.Header      `json:"headers"`
+	PostParams url.Values       `json:"post_params"`
+	GetParams  url.Values       `json:"get_params"`
+	Cookies    []*http.Cookie   `json:"cookies"`
+	Body       []byte           `json:"-"`
+	Parts      []*MultipartPart `json:"parts,omitempty"`
+	Time       time.Time        `json:"time"`
+}
+
+// requestFields has the fields of HTTPRequest without its JSON methods.
+type requestFields HTTPRequest
+
+func (req *HTTPRequest) MarshalJSON() ([]byte, error) {
+	body, encoding := EncodeJSONBody(req.Body)
+
+	return json.Marshal(&struct {
+		*requestFields
+		Body         string `json:"body"`
+		BodyEncoding string `json:"body_encoding"`
+		MimeType     string `json:"mime_type,omitempty"`
+	}{
+		requestFields: (*requestFields)(req),
+		Body:          body,
+		BodyEncoding:  encoding,
+		MimeType:      req.MimeType(),
+	})
+}
+
+// UnmarshalJSON reads requests stored before body_encoding was written as base64 bodies.
+func (req *HTTPRequest) UnmarshalJSON(data []byte) error {
+	fields := &struct {
+		*requestFields
+		Body         string `json:"body"`
+		BodyEncoding string `json:"body_encoding"`
+	}{requestFields: (*requestFields)(req)}
+
+	err := json.Unmarshal(data, fields)
+	if err != nil {
+		return err
+	}
+
+	req.Body, err = DecodeJSONBody(fields.Body, fields.BodyEncoding, BodyBase64)
+
+	return err
+}
+
+func (req *HTTPRequest) MimeType() string {
+	return DetectMIME(req.Header, req.DecodedBody())
 }
 
 func NewHTTPRequest(req *http.Request) *HTTPRequest {
-	parsedRequest := &HTTPRequest{}
+	parsedRequest := &HTTPRequest{
+		Time: time.Now(),
+	}
 
 	parsedRequest.Proto = req.Proto
 	parsedRequest.Method = req.Method
@@ -32,6 +91,10 @@ func NewHTTPRequest(req *http.Request) *HTTPRequest {
 	if colonIndex == -1 {
 		parsedRequest.Host = req.Host
 		parsedRequest.Port = "80"
+
+		if req.URL.Scheme == "https" {
+			parsedRequest.Port = "443"
+		}
 	} else {
 		parsedRequest.Host = req.Host[:colonIndex]
 		parsedRequest.Port = req.Host[colonIndex+1:]
@@ -57,16 +120,214 @@ func NewHTTPRequest(req *http.Request) *HTTPRequest {
 	parsedRequest.GetParams = req.URL.Query()
 	parsedRequest.Cookies = req.Cookies()
 
-	var body []byte
+	parsedRequest.Body, _ = io.ReadAll(req.Body)
+	parsedRequest.ParseParts()
 
-	if req.ContentLength == -1 {
-		body, _ = io.ReadAll(req.Body)
-	} else {
-		body = make([]byte, req.ContentLength)
-		req.Body.Read(body)
+	return parsedRequest
+}
+
+// ParseParts fills Parts from a multipart/form-data body and keeps the raw body for replay.
+func (req *HTTPRequest) ParseParts() {
+	req.Parts = nil
+
+	parts, err := ParseMultipart(req.Header, req.DecodedBody())
+	if err == nil && len(parts) > 0 {
+		req.Parts = parts
 	}
+}
 
-	parsedRequest.Body = body
+// SetParts re-encodes the body from parts, keeping the boundary of a multipart body.
+func (req *HTTPRequest) SetParts(parts []*MultipartPart) error {
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
 
-	return parsedRequest
+	boundary, ok := MultipartBoundary(req.Header)
+	if !ok {
+		boundary = multipart.NewWriter(nil).Boundary()
+		req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+	}
+
+	body, err := EncodeMultipart(parts, boundary)
+	if err != nil {
+		return err
+	}
+
+	req.Body = body
+	req.PostParams = url.Values{}
+	req.Header.Del("Content-Encoding")
+
+	if req.Header.Get("Content-Length") != "" {
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	req.ParseParts()
+
+	return nil
+}
+
+func ParseRawRequest(raw []byte, scheme string) (*HTTPRequest, error) {
+	raw = bytes.TrimLeft(raw, "\r\n\t ")
+
+	head, body := raw, []byte(nil)
+
+	if index := bytes.Index(raw, []byte("\r\n\r\n")); index != -1 {
+		head, body = raw[:index], raw[index+4:]
+	} else if index = bytes.Index(raw, []byte("\n\n")); index != -1 {
+		head, body = raw[:index], raw[index+2:]
+	}
+
+	reader := io.MultiReader(bytes.NewReader(bytes.TrimRight(head, "\r\n")), strings.NewReader("\r\n\r\n"))
+
+	req, err := http.ReadRequest(bufio.NewReader(reader))
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Host == "" {
+		return nil, ErrNoHost
+	}
+
+	if req.URL.Scheme == "" {
+		req.URL.Scheme = strings.ToLower(scheme)
+	}
+
+	if len(body) > 0 || req.Header.Get("Content-Length") != "" {
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	req.Header.Del("Transfer-Encoding")
+	req.TransferEncoding = nil
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	return NewHTTPRequest(req), nil
+}
+
+func (req *HTTPRequest) Clone() *HTTPRequest {
+	clone := *req
+	clone.Header = req.Header.Clone()
+	if clone.Header == nil {
+		clone.Header = http.Header{}
+	}
+
+	clone.PostParams = cloneValues(req.PostParams)
+	clone.GetParams = cloneValues(req.GetParams)
+	clone.Body = slices.Clone(req.Body)
+	clone.Parts = nil
+
+	for _, part := range req.Parts {
+		clone.Parts = append(clone.Parts, part.Clone())
+	}
+	clone.Cookies = make([]*http.Cookie, 0, len(req.Cookies))
+
+	for _, cookie := range req.Cookies {
+		copied := *cookie
+		clone.Cookies = append(clone.Cookies, &copied)
+	}
+
+	return &clone
+}
+
+func cloneValues(values url.Values) url.Values {
+	if values == nil {
+		return nil
+	}
+
+	return url.Values(http.Header(values).Clone())
+}
+
+func (req *HTTPRequest) DecodedBody() []byte {
+	if len(req.PostParams) > 0 {
+		body, err := url.QueryUnescape(req.PostParams.Encode())
+		if err != nil {
+			return []byte(req.PostParams.Encode())
+		}
+
+		return []byte(body)
+	}
+
+	// A body that can not be decoded is returned as sent.
+	body, _ := DecodeBody(req.Header, req.Body)
+
+	return body
+}
+
+func (req *HTTPRequest) URL() string {
+	requestURL := &url.URL{
+		Scheme:   strings.ToLower(req.Scheme),
+		Host:     req.hostPort(),
+		Path:     req.Path,
+		RawQuery: req.GetParams.Encode(),
+	}
+
+	return requestURL.String()
+}
+
+func (req *HTTPRequest) hostPort() string {
+	scheme := strings.ToLower(req.Scheme)
+
+	if req.Port == "" || (scheme == "http" && req.Port == "80") || (scheme == "https" && req.Port == "443") {
+		return req.Host
+	}
+
+	return net.JoinHostPort(req.Host, req.Port)
+}
+
+func (req *HTTPRequest) Raw() []byte {
+	body := req.Body
+	if len(req.PostParams) > 0 {
+		body = []byte(req.PostParams.Encode())
+	}
+
+	proto := req.Proto
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+
+	target := (&url.URL{Path: req.Path, RawQuery: req.GetParams.Encode()}).RequestURI()
+
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "%s %s %s\r\n", req.Method, target, proto)
+	fmt.Fprintf(buffer, "Host: %s\r\n", req.hostPort())
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch http.CanonicalHeaderKey(name) {
+		case "Host", "Content-Length":
+			continue
+		case "Cookie":
+			if len(req.Cookies) > 0 {
+				continue
+			}
+		}
+
+		for _, value := range req.Header[name] {
+			fmt.Fprintf(buffer, "%s: %s\r\n", name, value)
+		}
+	}
+
+	if len(req.Cookies) > 0 {
+		cookies := make([]string, 0, len(req.Cookies))
+		for _, cookie := range req.Cookies {
+			cookies = append(cookies, cookie.Name+"="+cookie.Value)
+		}
+
+		fmt.Fprintf(buffer, "Cookie: %s\r\n", strings.Join(cookies, "; "))
+	}
+
+	if len(body) > 0 {
+		fmt.Fprintf(buffer, "Content-Length: %d\r\n", len(body))
+	}
+
+	buffer.WriteString("\r\n")
+	buffer.Write(body)
+
+	return buffer.Bytes()
 }
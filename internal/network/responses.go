@@ -1,42 +1,141 @@
 package network
 
 import (
-	"compress/gzip"
+	"encoding/json"
 	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// HTTPResponse keeps the body decoded from its Content-Encoding. WireBody holds the bytes as the
+// server sent them and is only set when they differ from Body. DecodeError explains why a body
+// that declares an encoding is stored as sent.
 type HTTPResponse struct {
-	ID         string      `json:"id"`
-	Code       int         `json:"code"`
-	Message    string      `json:"message"`
-	Proto      string      `json:"proto"`
-	Headers    http.Header `json:"headers"`
-	Body       []byte      `json:"-"`
-	StringBody string      `json:"body"`
+	ID          string        `json:"id"`
+	Code        int           `json:"code"`
+	Message     string        `json:"message"`
+	Proto       string        `json:"proto"`
+	Headers     http.Header   `json:"headers"`
+	Body        []byte        `json:"-"`
+	WireBody    []byte        `json:"wire_body,omitempty"`
+	DecodeError string        `json:"decode_error,omitempty"`
+	Duration    time.Duration `json:"duration"`
 }
 
-func NewHTTPResponse(resp *http.Response) *HTTPResponse {
-	var reader io.ReadCloser
+// NewHTTPResponse reads and decodes the body of resp. An error is returned only when the body
+// can not be read; a body that can not be decoded is kept as sent, with DecodeError set.
+func NewHTTPResponse(resp *http.Response) (*HTTPResponse, error) {
+	wire, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
 
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		reader, _ = gzip.NewReader(resp.Body)
-	} else {
-		reader = resp.Body
+	parsedResponse := &HTTPResponse{
+		Code:    resp.StatusCode,
+		Message: resp.Status,
+		Proto:   resp.Proto,
+		Headers: resp.Header,
 	}
 
-	body, _ := io.ReadAll(reader)
+	parsedResponse.SetWireBody(wire)
 
-	defer reader.Close()
+	return parsedResponse, nil
+}
 
-	parsedResponse := &HTTPResponse{
-		Code:       resp.StatusCode,
-		Message:    resp.Status,
-		Proto:      resp.Proto,
-		Headers:    resp.Header,
-		Body:       body,
-		StringBody: string(body),
+// SetWireBody stores body as received and decodes it according to the Content-Encoding header.
+func (resp *HTTPResponse) SetWireBody(wire []byte) {
+	resp.WireBody = nil
+	resp.DecodeError = ""
+
+	body, err := DecodeBody(resp.Headers, wire)
+	if err != nil {
+		resp.DecodeError = err.Error()
+	} else if len(ContentEncodings(resp.Headers)) > 0 {
+		resp.WireBody = wire
+	}
+
+	resp.Body = body
+}
+
+// responseFields has the fields of HTTPResponse without its JSON methods.
+type responseFields HTTPResponse
+
+func (resp *HTTPResponse) MarshalJSON() ([]byte, error) {
+	body, encoding := EncodeJSONBody(resp.Body)
+
+	return json.Marshal(&struct {
+		*responseFields
+		Body         string `json:"body"`
+		BodyEncoding string `json:"body_encoding"`
+		MimeType     string `json:"mime_type,omitempty"`
+		Charset      string `json:"charset,omitempty"`
+	}{
+		responseFields: (*responseFields)(resp),
+		Body:           body,
+		BodyEncoding:   encoding,
+		MimeType:       resp.MimeType(),
+		Charset:        Charset(resp.Headers, resp.Body),
+	})
+}
+
+// UnmarshalJSON reads responses stored before body_encoding was written as text bodies.
+func (resp *HTTPResponse) UnmarshalJSON(data []byte) error {
+	fields := &struct {
+		*responseFields
+		Body         string `json:"body"`
+		BodyEncoding string `json:"body_encoding"`
+	}{responseFields: (*responseFields)(resp)}
+
+	err := json.Unmarshal(data, fields)
+	if err != nil {
+		return err
+	}
+
+	resp.Body, err = DecodeJSONBody(fields.Body, fields.BodyEncoding, BodyText)
+
+	return err
+}
+
+func (resp *HTTPResponse) MimeType() string {
+	return DetectMIME(resp.Headers, resp.Body)
+}
+
+// Decoded reports whether Body holds the content with its encodings removed.
+func (resp *HTTPResponse) Decoded() bool {
+	return resp.WireBody != nil
+}
+
+// ClientHeaders returns the headers that describe Body rather than the wire bytes: when the body
+// was decoded Content-Encoding is dropped and Content-Length is set to the decoded length.
+func (resp *HTTPResponse) ClientHeaders() http.Header {
+	header := resp.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	if !resp.Decoded() {
+		return header
+	}
+
+	header.Del("Content-Encoding")
+	header.Set("Content-Length", strconv.Itoa(len(resp.Body)))
+
+	return header
+}
+
+func (resp *HTTPResponse) ContentType() string {
+	contentType := resp.Headers.Get("Content-Type")
+	if contentType == "" {
+		return ""
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
 	}
 
-	return parsedResponse
+	return mediaType
 }
@@ -0,0 +1,151 @@
+package oob
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"proxy/internal/web-api/models"
+	"strings"
+	"time"
+)
+
+const (
+	dnsHeaderLength = 12
+	maxDNSPacket    = 512
+
+	dnsTypeA   = 1
+	dnsClassIN = 1
+)
+
+var errBadQuery = errors.New("malformed dns query")
+
+var dnsTypes = map[uint16]string{
+	1:   "A",
+	2:   "NS",
+	5:   "CNAME",
+	6:   "SOA",
+	15:  "MX",
+	16:  "TXT",
+	28:  "AAAA",
+	33:  "SRV",
+	255: "ANY",
+}
+
+type dnsQuestion struct {
+	name  string
+	qtype uint16
+	end   int
+}
+
+// ListenAndServeDNS answers UDP queries with the listener host and records those with a token.
+func (listener *Listener) ListenAndServeDNS() error {
+	conn, err := net.ListenPacket("udp", ":"+listener.dnsPort)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	buffer := make([]byte, maxDNSPacket)
+
+	for {
+		n, addr, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return err
+		}
+
+		reply := listener.serveDNS(buffer[:n], addr.String())
+		if reply != nil {
+			conn.WriteTo(reply, addr)
+		}
+	}
+}
+
+func (listener *Listener) serveDNS(packet []byte, source string) []byte {
+	question, err := parseQuestion(packet)
+	if err != nil {
+		return nil
+	}
+
+	typeName, ok := dnsTypes[question.qtype]
+	if !ok {
+		typeName = fmt.Sprintf("TYPE%d", question.qtype)
+	}
+
+	listener.record(strings.Split(question.name, "."), &models.Interaction{
+		Protocol: models.ProtocolDNS,
+		Source:   source,
+		Raw:      fmt.Sprintf("%s. IN %s", question.name, typeName),
+		Time:     time.Now(),
+	})
+
+	return listener.answer(packet, question)
+}
+
+func parseQuestion(packet []byte) (*dnsQuestion, error) {
+	if len(packet) < dnsHeaderLength || packet[2]&0x80 != 0 || binary.BigEndian.Uint16(packet[4:]) == 0 {
+		return nil, errBadQuery
+	}
+
+	labels := make([]string, 0)
+	offset := dnsHeaderLength
+
+	for {
+		if offset >= len(packet) {
+			return nil, errBadQuery
+		}
+
+		length := int(packet[offset])
+		offset++
+
+		if length == 0 {
+			break
+		}
+
+		if length&0xC0 != 0 || offset+length > len(packet) {
+			return nil, errBadQuery
+		}
+
+		labels = append(labels, string(packet[offset:offset+length]))
+		offset += length
+	}
+
+	if offset+4 > len(packet) {
+		return nil, errBadQuery
+	}
+
+	return &dnsQuestion{
+		name:  strings.Join(labels, "."),
+		qtype: binary.BigEndian.Uint16(packet[offset:]),
+		end:   offset + 4,
+	}, nil
+}
+
+// answer builds an authoritative reply, with an A record for the listener host on A queries.
+func (listener *Listener) answer(packet []byte, question *dnsQuestion) []byte {
+	ip := net.ParseIP(listener.host).To4()
+	withRecord := question.qtype == dnsTypeA && ip != nil
+
+	reply := make([]byte, dnsHeaderLength, question.end+16)
+	copy(reply, packet[:2])
+
+	reply[2] = 0x84 | packet[2]&0x79
+	binary.BigEndian.PutUint16(reply[4:], 1)
+
+	if withRecord {
+		binary.BigEndian.PutUint16(reply[6:], 1)
+	}
+
+	reply = append(reply, packet[dnsHeaderLength:question.end]...)
+
+	if withRecord {
+		reply = append(reply, 0xC0, dnsHeaderLength)
+		reply = binary.BigEndian.AppendUint16(reply, dnsTypeA)
+		reply = binary.BigEndian.AppendUint16(reply, dnsClassIN)
+		reply = binary.BigEndian.AppendUint32(reply, 0)
+		reply = binary.BigEndian.AppendUint16(reply, uint16(len(ip)))
+		reply = append(reply, ip...)
+	}
+
+	return reply
+}
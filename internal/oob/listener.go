@@ -0,0 +1,217 @@
+package oob
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"proxy/internal/web-api/models"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	PlaceholderURL    = "{{oob.url}}"
+	PlaceholderDomain = "{{oob.domain}}"
+
+	tokenBytes = 8
+	maxRaw     = 64 << 10
+
+	maxTokens       = 100000
+	maxInteractions = 100
+)
+
+type tokenState struct {
+	meta         *models.InteractionToken
+	interactions []*models.Interaction
+}
+
+// Listener records HTTP and DNS requests for the tokens it handed out.
+type Listener struct {
+	mu       sync.Mutex
+	host     string
+	domain   string
+	httpPort string
+	dnsPort  string
+	lastID   int
+	tokens   map[string]*tokenState
+	order    []string
+}
+
+func NewListener(host, domain, httpPort, dnsPort string) *Listener {
+	return &Listener{
+		host:     host,
+		domain:   strings.ToLower(strings.Trim(domain, ".")),
+		httpPort: httpPort,
+		dnsPort:  dnsPort,
+		tokens:   make(map[string]*tokenState),
+	}
+}
+
+func (listener *Listener) ListenAndServeHTTP() error {
+	return http.ListenAndServe(":"+listener.httpPort, listener)
+}
+
+// Token issues a new token described by meta, forgetting the oldest ones past the limit.
+func (listener *Listener) Token(meta *models.InteractionToken) *models.InteractionToken {
+	raw := make([]byte, tokenBytes)
+	rand.Read(raw)
+
+	issued := *meta
+	issued.Token = hex.EncodeToString(raw)
+	issued.URL = listener.HTTPURL(issued.Token)
+	issued.Domain = listener.Domain(issued.Token)
+	issued.CreatedAt = time.Now()
+
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+
+	listener.tokens[issued.Token] = &tokenState{meta: &issued, interactions: make([]*models.Interaction, 0)}
+	listener.order = append(listener.order, issued.Token)
+
+	if len(listener.order) > maxTokens {
+		delete(listener.tokens, listener.order[0])
+		listener.order = listener.order[1:]
+	}
+
+	copied := issued
+
+	return &copied
+}
+
+func (listener *Listener) HTTPURL(token string) string {
+	return "http://" + net.JoinHostPort(listener.host, listener.httpPort) + "/" + token
+}
+
+func (listener *Listener) Domain(token string) string {
+	return token + "." + listener.domain
+}
+
+// Substitute replaces the placeholders in text with the addresses of token.
+func Substitute(text string, token *models.InteractionToken) string {
+	return strings.NewReplacer(PlaceholderURL, token.URL, PlaceholderDomain, token.Domain).Replace(text)
+}
+
+func HasPlaceholder(text string) bool {
+	return strings.Contains(text, PlaceholderURL) || strings.Contains(text, PlaceholderDomain)
+}
+
+// Attach records the payload and the transaction that carried the token once it has been sent.
+func (listener *Listener) Attach(token, payload, requestID string) {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+
+	state, ok := listener.tokens[token]
+	if ok {
+		state.meta.Payload = payload
+		state.meta.RequestID = requestID
+	}
+}
+
+func (listener *Listener) GetToken(token string) (*models.InteractionToken, error) {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+
+	state, ok := listener.tokens[token]
+	if !ok {
+		return nil, models.ErrTokenNotFound
+	}
+
+	meta := *state.meta
+
+	return &meta, nil
+}
+
+func (listener *Listener) Interactions(token string) []*models.Interaction {
+	return listener.List(&models.InteractionsFilter{Token: token})
+}
+
+// List returns matching interactions in the order they arrived.
+func (listener *Listener) List(filter *models.InteractionsFilter) []*models.Interaction {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+
+	interactions := make([]*models.Interaction, 0)
+
+	for token, state := range listener.tokens {
+		if filter.Token != "" && token != filter.Token {
+			continue
+		}
+
+		if (filter.Origin != "" && state.meta.Origin != filter.Origin) ||
+			(filter.JobID != "" && state.meta.JobID != filter.JobID) {
+			continue
+		}
+
+		for _, interaction := range state.interactions {
+			if filter.Protocol != "" && interaction.Protocol != filter.Protocol {
+				continue
+			}
+
+			copied := *interaction
+			meta := *state.meta
+			copied.Origin = &meta
+			interactions = append(interactions, &copied)
+		}
+	}
+
+	slices.SortFunc(interactions, func(a, b *models.Interaction) int {
+		aID, _ := strconv.Atoi(a.ID)
+		bID, _ := strconv.Atoi(b.ID)
+
+		return aID - bID
+	})
+
+	return interactions
+}
+
+func (listener *Listener) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	token, _, _ := strings.Cut(strings.TrimPrefix(request.URL.Path, "/"), "/")
+
+	host, _, err := net.SplitHostPort(request.Host)
+	if err != nil {
+		host = request.Host
+	}
+
+	raw, err := httputil.DumpRequest(request, true)
+	if err != nil {
+		raw = []byte(request.Method + " " + request.URL.String())
+	}
+
+	if len(raw) > maxRaw {
+		raw = raw[:maxRaw]
+	}
+
+	listener.record(append([]string{token}, strings.Split(host, ".")...), &models.Interaction{
+		Protocol: models.ProtocolHTTP,
+		Source:   request.RemoteAddr,
+		Raw:      string(raw),
+		Time:     time.Now(),
+	})
+
+	writer.WriteHeader(http.StatusOK)
+}
+
+// record stores the interaction under the first candidate that is an issued token.
+func (listener *Listener) record(candidates []string, interaction *models.Interaction) {
+	listener.mu.Lock()
+	defer listener.mu.Unlock()
+
+	for _, candidate := range candidates {
+		state, ok := listener.tokens[strings.ToLower(candidate)]
+		if !ok || len(state.interactions) >= maxInteractions {
+			continue
+		}
+
+		listener.lastID++
+		interaction.ID = strconv.Itoa(listener.lastID)
+		interaction.Token = state.meta.Token
+		state.interactions = append(state.interactions, interaction)
+
+		return
+	}
+}
@@ -0,0 +1,95 @@
+package passive
+
+import (
+	"proxy/internal/web-api/models"
+	"regexp"
+	"strings"
+)
+
+const (
+	IssueSensitiveURL    = "sensitive_url"
+	IssueErrorDisclosure = "error_disclosure"
+	IssueMixedContent    = "mixed_content"
+)
+
+var (
+	sensitiveParam = regexp.MustCompile(`(?i)^(pass(word|wd)?|pwd|secret|token|access_?token|refresh_?token|` +
+		`id_?token|api_?key|apikey|auth|authorization|session(_?id)?|sid|jsessionid|phpsessid|jwt|private_?key|ssn)$`)
+
+	errorPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`Traceback \(most recent call last\)`),
+		regexp.MustCompile(`\bat [\w$.]+\([\w$]+\.java:\d+\)`),
+		regexp.MustCompile(`(?i)<b>(Fatal error|Warning|Parse error)</b>:.+ on line <b>\d+</b>`),
+		regexp.MustCompile(`Server Error in '[^']*' Application`),
+		regexp.MustCompile(`\bSystem\.[A-Z]\w+Exception\b`),
+		regexp.MustCompile(`goroutine \d+ \[running\]`),
+		regexp.MustCompile(`(?i)you have an error in your SQL syntax`),
+		regexp.MustCompile(`\bORA-\d{5}\b`),
+		regexp.MustCompile(`\bSQLSTATE\[\w+\]`),
+		regexp.MustCompile(`(?i)unclosed quotation mark after the character string`),
+		regexp.MustCompile(`\bat [\w.<>]+ \(/[^)]+\.js:\d+:\d+\)`),
+	}
+
+	mixedContent = regexp.MustCompile(`(?i)<(?:script|img|iframe|link|audio|video|source|embed|object|form)\b` +
+		`[^>]*?\b(?:src|href|data|action)\s*=\s*["']?(http://[^"'\s>]+)`)
+)
+
+func checkSensitiveURL(exchange *exchange) []*models.Issue {
+	issues := make([]*models.Issue, 0)
+
+	for name, values := range exchange.request.GetParams {
+		if !sensitiveParam.MatchString(name) || len(values) == 0 || values[0] == "" {
+			continue
+		}
+
+		issues = append(issues, &models.Issue{
+			Type:     IssueSensitiveURL,
+			Severity: models.SeverityMedium,
+			Title:    "Sensitive data is sent in the URL",
+			Detail:   "URLs end up in logs, browser history and Referer headers",
+			Param:    name,
+		})
+	}
+
+	return issues
+}
+
+func checkErrorDisclosure(exchange *exchange) []*models.Issue {
+	if exchange.body == "" || strings.HasPrefix(exchange.contentType, "image/") {
+		return nil
+	}
+
+	for _, pattern := range errorPatterns {
+		match := pattern.FindString(exchange.body)
+		if match == "" {
+			continue
+		}
+
+		return []*models.Issue{{
+			Type:     IssueErrorDisclosure,
+			Severity: models.SeverityMedium,
+			Title:    "Response contains an error message or stack trace",
+			Evidence: evidence(match),
+		}}
+	}
+
+	return nil
+}
+
+func checkMixedContent(exchange *exchange) []*models.Issue {
+	if !exchange.https || !exchange.isHTML() {
+		return nil
+	}
+
+	match := mixedContent.FindStringSubmatch(exchange.body)
+	if match == nil {
+		return nil
+	}
+
+	return []*models.Issue{{
+		Type:     IssueMixedContent,
+		Severity: models.SeverityLow,
+		Title:    "HTTPS page loads resources over plain HTTP",
+		Evidence: evidence(match[1]),
+	}}
+}
@@ -0,0 +1,151 @@
+package passive
+
+import (
+	"net/http"
+	"proxy/internal/web-api/models"
+	"regexp"
+	"strings"
+)
+
+const (
+	IssueMissingCSP             = "missing_csp"
+	IssueMissingHSTS            = "missing_hsts"
+	IssueMissingFrameOptions    = "missing_frame_options"
+	IssueInsecureCookie         = "insecure_cookie"
+	IssueVersionDisclosure      = "version_disclosure"
+	IssueCacheableAuthenticated = "cacheable_authenticated"
+)
+
+var (
+	versionHeaders = []string{"Server", "X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version", "X-Generator"}
+	versionPattern = regexp.MustCompile(`\d+\.\d+`)
+)
+
+func checkSecurityHeaders(exchange *exchange) []*models.Issue {
+	if !exchange.isHTML() || !exchange.isSuccess() {
+		return nil
+	}
+
+	headers := exchange.response.Headers
+	issues := make([]*models.Issue, 0)
+
+	csp := headers.Get("Content-Security-Policy")
+	if csp == "" {
+		issues = append(issues, &models.Issue{
+			Type:     IssueMissingCSP,
+			Severity: models.SeverityLow,
+			Title:    "Content-Security-Policy header is missing",
+		})
+	}
+
+	if headers.Get("X-Frame-Options") == "" && !strings.Contains(csp, "frame-ancestors") {
+		issues = append(issues, &models.Issue{
+			Type:     IssueMissingFrameOptions,
+			Severity: models.SeverityLow,
+			Title:    "Page can be framed by other sites",
+			Detail:   "neither X-Frame-Options nor CSP frame-ancestors is set",
+		})
+	}
+
+	if exchange.https && headers.Get("Strict-Transport-Security") == "" {
+		issues = append(issues, &models.Issue{
+			Type:     IssueMissingHSTS,
+			Severity: models.SeverityLow,
+			Title:    "Strict-Transport-Security header is missing",
+		})
+	}
+
+	return issues
+}
+
+func checkCookies(exchange *exchange) []*models.Issue {
+	issues := make([]*models.Issue, 0)
+
+	for _, cookie := range (&http.Response{Header: exchange.response.Headers}).Cookies() {
+		if cookie.MaxAge < 0 {
+			continue
+		}
+
+		missing := make([]string, 0, 3)
+
+		if exchange.https && !cookie.Secure {
+			missing = append(missing, "Secure")
+		}
+
+		if !cookie.HttpOnly {
+			missing = append(missing, "HttpOnly")
+		}
+
+		if cookie.SameSite == 0 || cookie.SameSite == http.SameSiteDefaultMode {
+			missing = append(missing, "SameSite")
+		}
+
+		if len(missing) == 0 {
+			continue
+		}
+
+		issues = append(issues, &models.Issue{
+			Type:     IssueInsecureCookie,
+			Severity: models.SeverityLow,
+			Title:    "Cookie is set without security attributes",
+			Detail:   "missing " + strings.Join(missing, ", "),
+			Param:    cookie.Name,
+			Evidence: evidence(cookie.Raw),
+		})
+	}
+
+	return issues
+}
+
+func checkVersionDisclosure(exchange *exchange) []*models.Issue {
+	issues := make([]*models.Issue, 0)
+
+	for _, name := range versionHeaders {
+		value := exchange.response.Headers.Get(name)
+		if value == "" || !versionPattern.MatchString(value) {
+			continue
+		}
+
+		issues = append(issues, &models.Issue{
+			Type:     IssueVersionDisclosure,
+			Severity: models.SeverityInfo,
+			Title:    "Server software version is disclosed",
+			Param:    name,
+			Evidence: evidence(name + ": " + value),
+		})
+	}
+
+	return issues
+}
+
+// checkCacheableAuthenticated flags authenticated responses that shared caches are allowed to store.
+func checkCacheableAuthenticated(exchange *exchange) []*models.Issue {
+	if !exchange.authenticated() || !exchange.isSuccess() {
+		return nil
+	}
+
+	headers := exchange.response.Headers
+	cacheControl := strings.ToLower(strings.Join(headers.Values("Cache-Control"), ","))
+
+	for _, directive := range []string{"no-store", "private", "no-cache"} {
+		if strings.Contains(cacheControl, directive) {
+			return nil
+		}
+	}
+
+	if cacheControl == "" && strings.Contains(strings.ToLower(headers.Get("Pragma")), "no-cache") {
+		return nil
+	}
+
+	detail := "Cache-Control is missing"
+	if cacheControl != "" {
+		detail = "Cache-Control: " + cacheControl
+	}
+
+	return []*models.Issue{{
+		Type:     IssueCacheableAuthenticated,
+		Severity: models.SeverityMedium,
+		Title:    "Authenticated response can be cached",
+		Detail:   detail,
+	}}
+}
@@ -0,0 +1,105 @@
+package passive
+
+import (
+	"net/http"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"strings"
+)
+
+const maxBodyScan = 1 << 20
+
+type exchange struct {
+	request     *network.HTTPRequest
+	response    *network.HTTPResponse
+	https       bool
+	contentType string
+	body        string
+}
+
+type check func(exchange *exchange) []*models.Issue
+
+var checks = []check{
+	checkSecurityHeaders,
+	checkCookies,
+	checkSensitiveURL,
+	checkErrorDisclosure,
+	checkVersionDisclosure,
+	checkMixedContent,
+	checkCacheableAuthenticated,
+}
+
+type Analyzer struct {
+	signatures []*Signature
+}
+
+func NewAnalyzer(signatures []*Signature) (*Analyzer, error) {
+	for _, signature := range signatures {
+		err := signature.compile()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Analyzer{signatures: signatures}, nil
+}
+
+// Analyze looks for issues in a saved exchange without sending any traffic.
+func (analyzer *Analyzer) Analyze(request *network.HTTPRequest, response *network.HTTPResponse) []*models.Issue {
+	if response == nil {
+		return nil
+	}
+
+	body := response.Body
+	if len(body) > maxBodyScan {
+		body = body[:maxBodyScan]
+	}
+
+	exchange := &exchange{
+		request:     request,
+		response:    response,
+		https:       strings.EqualFold(request.Scheme, "https"),
+		contentType: response.ContentType(),
+		body:        string(body),
+	}
+
+	issues := analyzer.checkSecrets(exchange)
+	for _, check := range checks {
+		issues = append(issues, check(exchange)...)
+	}
+
+	for _, issue := range issues {
+		issue.Host = strings.ToLower(request.Host)
+		issue.Path = request.Path
+		issue.RequestID = request.ID
+		issue.Count = 1
+		issue.FirstSeen = request.Time
+		issue.LastSeen = request.Time
+	}
+
+	return issues
+}
+
+func (exchange *exchange) isHTML() bool {
+	return exchange.contentType == "text/html" || exchange.contentType == "application/xhtml+xml"
+}
+
+func (exchange *exchange) isSuccess() bool {
+	return exchange.response.Code >= http.StatusOK && exchange.response.Code < http.StatusMultipleChoices
+}
+
+func (exchange *exchange) authenticated() bool {
+	return exchange.request.Header.Get("Authorization") != "" || len(exchange.request.Cookies) > 0 ||
+		exchange.request.Header.Get("Cookie") != ""
+}
+
+func evidence(text string) string {
+	const maxEvidence = 200
+
+	text = strings.TrimSpace(text)
+	if len(text) > maxEvidence {
+		return text[:maxEvidence] + "..."
+	}
+
+	return text
+}
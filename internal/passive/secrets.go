@@ -0,0 +1,238 @@
+package passive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"proxy/internal/web-api/models"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const IssueSecret = "exposed_secret"
+
+var ErrBadSignature = errors.New("invalid secret signature")
+
+// Signature describes a kind of secret. When the pattern has a capturing group, the first
+// group is the secret itself; matches with lower Shannon entropy than MinEntropy
+// (bits per character) are ignored as placeholders.
+type Signature struct {
+	Name       string  `json:"name"`
+	Pattern    string  `json:"pattern"`
+	Severity   string  `json:"severity,omitempty"`
+	MinEntropy float64 `json:"min_entropy,omitempty"`
+	Disabled   bool    `json:"disabled,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+func DefaultSignatures() []*Signature {
+	return []*Signature{
+		{Name: "aws_access_key_id", Severity: models.SeverityHigh, MinEntropy: 3,
+			Pattern: `\b((?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16})\b`},
+		{Name: "aws_secret_access_key", Severity: models.SeverityHigh, MinEntropy: 4,
+			Pattern: `(?i)aws.{0,20}?(?:secret|private).{0,20}?["':=\s]+([A-Za-z0-9/+=]{40})\b`},
+		{Name: "gcp_api_key", Severity: models.SeverityHigh, MinEntropy: 3.5,
+			Pattern: `\b(AIza[0-9A-Za-z_\-]{35})`},
+		{Name: "gcp_service_account", Severity: models.SeverityHigh,
+			Pattern: `"private_key_id"\s*:\s*"([0-9a-f]{40})"`},
+		{Name: "private_key", Severity: models.SeverityHigh,
+			Pattern: `-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`},
+		{Name: "github_token", Severity: models.SeverityHigh, MinEntropy: 3.5,
+			Pattern: `\b(gh[pousr]_[A-Za-z0-9]{36,})\b`},
+		{Name: "slack_token", Severity: models.SeverityHigh, MinEntropy: 3,
+			Pattern: `\b(xox[baprs]-[0-9A-Za-z\-]{10,})`},
+		{Name: "stripe_key", Severity: models.SeverityHigh, MinEntropy: 3.5,
+			Pattern: `\b((?:sk|rk)_live_[0-9A-Za-z]{24,})\b`},
+		{Name: "jwt", Severity: models.SeverityMedium,
+			Pattern: `\b(eyJ[A-Za-z0-9_\-]{8,}\.eyJ[A-Za-z0-9_\-]{8,}\.[A-Za-z0-9_\-]{8,})`},
+		{Name: "generic_api_key", Severity: models.SeverityMedium, MinEntropy: 3.5,
+			Pattern: `(?i)\b(?:api[_\-]?key|secret[_\-]?key|client[_\-]?secret|access[_\-]?token|auth[_\-]?token)\b` +
+				`["']?\s*[:=]\s*["']([A-Za-z0-9_\-+/=.]{16,})["']`},
+		{Name: "password_field", Severity: models.SeverityMedium,
+			Pattern: `(?i)["']?\b(?:password|passwd|pwd)\b["']?\s*[:=]\s*["']([^"'\s]{4,})["']`},
+		{Name: "password_input", Severity: models.SeverityMedium,
+			Pattern: `(?i)<input\b[^>]*\btype\s*=\s*["']?password\b[^>]*\bvalue\s*=\s*["']([^"']+)["']`},
+		{Name: "internal_ip", Severity: models.SeverityLow,
+			Pattern: `\b(10\.\d{1,3}\.\d{1,3}\.\d{1,3}|172\.(?:1[6-9]|2\d|3[01])\.\d{1,3}\.\d{1,3}|` +
+				`192\.168\.\d{1,3}\.\d{1,3})\b`},
+	}
+}
+
+// LoadSignatures reads signatures from a JSON file and merges them into the default set:
+// a signature with a default's name replaces it, and a disabled one removes it.
+func LoadSignatures(path string) ([]*Signature, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var custom []*Signature
+
+	err = json.Unmarshal(data, &custom)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadSignature, err)
+	}
+
+	signatures := DefaultSignatures()
+
+	for _, signature := range custom {
+		signatures = slices.DeleteFunc(signatures, func(existing *Signature) bool {
+			return existing.Name == signature.Name
+		})
+
+		if !signature.Disabled {
+			signatures = append(signatures, signature)
+		}
+	}
+
+	return signatures, nil
+}
+
+func (signature *Signature) compile() error {
+	if signature.Name == "" || signature.Pattern == "" {
+		return fmt.Errorf("%w: name and pattern are required", ErrBadSignature)
+	}
+
+	if signature.Severity == "" {
+		signature.Severity = models.SeverityMedium
+	}
+
+	if !models.IsValidSeverity(signature.Severity) {
+		return fmt.Errorf("%w: %s: unknown severity %q", ErrBadSignature, signature.Name, signature.Severity)
+	}
+
+	var err error
+
+	signature.pattern, err = regexp.Compile(signature.Pattern)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrBadSignature, signature.Name, err)
+	}
+
+	return nil
+}
+
+// find returns the offset and length of the first secret in text.
+func (signature *Signature) find(text string) (int, int, bool) {
+	for _, match := range signature.pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[0], match[1]
+		if len(match) > 3 && match[2] >= 0 {
+			start, end = match[2], match[3]
+		}
+
+		if entropy(text[start:end]) >= signature.MinEntropy {
+			return start, end - start, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+func (analyzer *Analyzer) checkSecrets(exchange *exchange) []*models.Issue {
+	issues := make([]*models.Issue, 0)
+
+	for _, signature := range analyzer.signatures {
+		if exchange.scanBody() {
+			offset, length, ok := signature.find(exchange.body)
+			if ok {
+				issues = append(issues, secretIssue(signature, exchange.body[offset:offset+length],
+					&models.IssueMatch{Location: models.MatchBody, Offset: offset, Length: length}))
+			}
+		}
+
+		for _, name := range sortedHeaders(exchange) {
+			for i, value := range exchange.response.Headers[name] {
+				offset, length, ok := signature.find(value)
+				if !ok {
+					continue
+				}
+
+				issues = append(issues, secretIssue(signature, value[offset:offset+length], &models.IssueMatch{
+					Location: models.MatchHeader,
+					Header:   name,
+					Value:    i,
+					Offset:   offset,
+					Length:   length,
+				}))
+			}
+		}
+	}
+
+	return issues
+}
+
+func secretIssue(signature *Signature, secret string, match *models.IssueMatch) *models.Issue {
+	location := match.Location
+	if match.Header != "" {
+		location = match.Header + " header"
+	}
+
+	return &models.Issue{
+		Type:     IssueSecret,
+		Severity: signature.Severity,
+		Title:    "Response exposes a secret or credential",
+		Detail:   fmt.Sprintf("%s found in the response %s", signature.Name, location),
+		Param:    signature.Name,
+		Evidence: mask(secret),
+		Match:    match,
+	}
+}
+
+func (exchange *exchange) scanBody() bool {
+	for _, prefix := range []string{"image/", "audio/", "video/", "font/"} {
+		if strings.HasPrefix(exchange.contentType, prefix) {
+			return false
+		}
+	}
+
+	return exchange.body != ""
+}
+
+func sortedHeaders(exchange *exchange) []string {
+	names := make([]string, 0, len(exchange.response.Headers))
+	for name := range exchange.response.Headers {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// mask keeps only the edges of a secret so that issues do not spread it further.
+func mask(secret string) string {
+	const visible = 4
+
+	secret = evidence(secret)
+	if len(secret) <= visible*3 {
+		return strings.Repeat("*", len(secret))
+	}
+
+	return secret[:visible] + strings.Repeat("*", len(secret)-visible*2) + secret[len(secret)-visible:]
+}
+
+func entropy(text string) float64 {
+	if text == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+
+	for _, char := range text {
+		counts[char]++
+		total++
+	}
+
+	result := 0.0
+
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		result -= p * math.Log2(p)
+	}
+
+	return result
+}
@@ -6,17 +6,19 @@ import (
 	"net/http"
 	"proxy/internal/network"
 	"proxy/internal/proxy/certs"
+	"proxy/internal/web-api/models"
 	"proxy/internal/web-api/usecases"
+	"time"
 )
 
 type ProxyHandler struct {
-	ca      *tls.Certificate
-	storage usecases.WebApiInterface
+	ca       *tls.Certificate
+	projects usecases.StorageSource
 }
 
-func NewProxyHandler(certPath, keyPath string, storage usecases.WebApiInterface) *ProxyHandler {
+func NewProxyHandler(certPath, keyPath string, projects usecases.StorageSource) *ProxyHandler {
 	proxy := &ProxyHandler{
-		storage: storage,
+		projects: projects,
 	}
 
 	var err error
@@ -39,12 +41,32 @@ func (proxy *ProxyHandler) ServeHTTP(writer http.ResponseWriter, req *http.Reque
 		err  error
 	)
 
+	// The request and its response go to the project that was current when the request came in.
+	storage := proxy.projects.Storage()
+	scope := scopeOf(storage)
+
 	if parsedReq.Method == http.MethodConnect {
-		err = proxy.handleConnect(writer, parsedReq)
+		switch {
+		case scope.AllowsHost("https", parsedReq.Host, parsedReq.Port):
+			err = proxy.handleConnect(writer, parsedReq, storage, scope)
+		case scope.Blocks():
+			http.Error(writer, models.ErrOutOfScope.Error(), http.StatusForbidden)
+		default:
+			err = proxy.tunnel(writer, parsedReq)
+		}
+
+		return
+	}
+
+	inScope := scope.Allows(parsedReq)
+	if !inScope && scope.Blocks() {
+		http.Error(writer, models.ErrOutOfScope.Error(), http.StatusForbidden)
 
 		return
 	}
 
+	start := time.Now()
+
 	resp, err = proxy.HandleHTTP(writer, parsedReq)
 	if err != nil {
 		return
@@ -52,16 +74,30 @@ func (proxy *ProxyHandler) ServeHTTP(writer http.ResponseWriter, req *http.Reque
 
 	defer resp.Body.Close()
 
-	id, err := proxy.storage.SaveRequest(parsedReq)
+	parsedResp, err := network.NewHTTPResponse(resp)
 	if err != nil {
-		log.Println("Something went wrong while saving request", err)
+		log.Println("Something went wrong while reading response", err)
+		http.Error(writer, "Failed to read response", http.StatusBadGateway)
 
 		return
 	}
 
-	parsedResp := network.NewHTTPResponse(resp)
+	parsedResp.Duration = time.Since(start)
+
+	if !inScope {
+		proxy.SendNewResponse(writer, parsedResp)
+
+		return
+	}
 
-	err = proxy.storage.SaveResponse(parsedResp, id)
+	id, err := storage.SaveRequest(parsedReq)
+	if err != nil {
+		log.Println("Something went wrong while saving request", err)
+
+		return
+	}
+
+	err = storage.SaveResponse(parsedResp, id)
 	if err != nil {
 		log.Println("Something went wrong while saving response", err)
 
@@ -70,3 +106,16 @@ func (proxy *ProxyHandler) ServeHTTP(writer http.ResponseWriter, req *http.Reque
 
 	proxy.SendNewResponse(writer, parsedResp)
 }
+
+// scopeOf falls back to recording everything when the scope can not be loaded,
+// so that a storage error does not stop the proxy.
+func scopeOf(storage usecases.WebApiInterface) *models.Scope {
+	scope, err := storage.GetScope()
+	if err != nil {
+		log.Println("error getting scope", err)
+
+		return &models.Scope{}
+	}
+
+	return scope
+}
@@ -13,6 +13,17 @@ import (
 )
 
 func (proxy *ProxyHandler) HandleHTTP(writer http.ResponseWriter, req *network.HTTPRequest) (*http.Response, error) {
+	resp, err := proxy.Send(req)
+	if err != nil {
+		http.Error(writer, "Failed to send request", http.StatusInternalServerError)
+
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (proxy *ProxyHandler) Send(req *network.HTTPRequest) (*http.Response, error) {
 	var reader io.Reader
 
 	if len(req.PostParams) > 0 {
@@ -21,18 +32,24 @@ func (proxy *ProxyHandler) HandleHTTP(writer http.ResponseWriter, req *network.H
 		reader = bytes.NewReader(req.Body)
 	}
 
-	newReq, err := http.NewRequest(req.Method,
-		fmt.Sprintf("%s://%s:%s%s", req.Scheme, req.Host, req.Port, req.Path), reader)
-	newReq.Header = req.Header
+	newReq, err := http.NewRequest(req.Method, req.URL(), reader)
+	if err != nil {
+		log.Println("Something went wrong while building request:", err)
 
-	for _, cookie := range req.Cookies {
-		newReq.AddCookie(cookie)
+		return nil, err
 	}
 
-	for key, value := range req.GetParams {
-		for _, v := range value {
-			newReq.URL.Query().Add(key, v)
-		}
+	newReq.Header = req.Header.Clone()
+	if newReq.Header == nil {
+		newReq.Header = http.Header{}
+	}
+
+	if len(req.Cookies) > 0 {
+		newReq.Header.Del("Cookie")
+	}
+
+	for _, cookie := range req.Cookies {
+		newReq.AddCookie(cookie)
 	}
 
 	var config *certs.TLSConfig
@@ -63,7 +80,6 @@ func (proxy *ProxyHandler) HandleHTTP(writer http.ResponseWriter, req *network.H
 
 	resp, err := client.Do(newReq)
 	if err != nil {
-		http.Error(writer, "Failed to send request", http.StatusInternalServerError)
 		log.Println("Something went wrong while sending request:", err)
 
 		return nil, err
@@ -73,12 +89,14 @@ func (proxy *ProxyHandler) HandleHTTP(writer http.ResponseWriter, req *network.H
 }
 
 func (proxy *ProxyHandler) SendNewResponse(writer http.ResponseWriter, resp *network.HTTPResponse) {
-	network.CopyHeaders(writer.Header(), resp.Headers)
+	header := resp.ClientHeaders()
+
+	network.CopyHeaders(writer.Header(), header)
 	writer.WriteHeader(resp.Code)
 
 	writer.Write([]byte(fmt.Sprintf("%s %s\n", resp.Proto, resp.Message)))
 
-	for k, v := range resp.Headers {
+	for k, v := range header {
 		writer.Write([]byte(fmt.Sprintf("%s: %s\n", k, strings.Join(v, ", "))))
 	}
 
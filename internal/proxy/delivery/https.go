@@ -11,15 +11,21 @@ import (
 	"net/http"
 	"proxy/internal/network"
 	"proxy/internal/proxy/certs"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/usecases"
 	"sync"
+	"time"
 )
 
+const tunnelDialTimeout = 10 * time.Second
+
 type MutexBuffer struct {
 	buf []byte
 	mu  sync.Mutex
 }
 
-func (proxy *ProxyHandler) handleConnect(writer http.ResponseWriter, req *network.HTTPRequest) error {
+func (proxy *ProxyHandler) handleConnect(writer http.ResponseWriter, req *network.HTTPRequest,
+	storage usecases.WebApiInterface, scope *models.Scope) error {
 	config, err := certs.GetTLSConfig(req, proxy.ca)
 	if err != nil {
 		log.Println("error getting tls config", err)
@@ -58,6 +64,8 @@ func (proxy *ProxyHandler) handleConnect(writer http.ResponseWriter, req *networ
 		mu:  sync.Mutex{},
 	}
 
+	start := time.Now()
+
 	go transfer(config.Conn, netConn, wg, respBuf)
 	go transfer(netConn, config.Conn, wg, reqBuf)
 
@@ -86,16 +94,29 @@ func (proxy *ProxyHandler) handleConnect(writer http.ResponseWriter, req *networ
 	parsedReq := network.NewHTTPRequest(tlsReq)
 	parsedReq.Scheme = "HTTPS"
 	parsedReq.Port = "443"
+	parsedReq.Time = start
 
-	id, err := proxy.storage.SaveRequest(parsedReq)
+	if !scope.Allows(parsedReq) {
+		return nil
+	}
+
+	parsedResp, err := network.NewHTTPResponse(tlsResp)
+	if err != nil {
+		log.Println("error reading response body", err)
+
+		return err
+	}
+
+	parsedResp.Duration = time.Since(start)
+
+	id, err := storage.SaveRequest(parsedReq)
 	if err != nil {
 		log.Println("Something went wrong while saving request", err)
 
 		return err
 	}
 
-	parsedResp := network.NewHTTPResponse(tlsResp)
-	err = proxy.storage.SaveResponse(parsedResp, id)
+	err = storage.SaveResponse(parsedResp, id)
 	if err != nil {
 		log.Println("Something went wrong while saving response", err)
 
@@ -105,6 +126,47 @@ func (proxy *ProxyHandler) handleConnect(writer http.ResponseWriter, req *networ
 	return nil
 }
 
+// tunnel passes the connection through without decrypting it, so nothing is recorded.
+func (proxy *ProxyHandler) tunnel(writer http.ResponseWriter, req *network.HTTPRequest) error {
+	upstream, err := net.DialTimeout("tcp", net.JoinHostPort(req.Host, req.Port), tunnelDialTimeout)
+	if err != nil {
+		http.Error(writer, "no upstream", http.StatusBadGateway)
+
+		return err
+	}
+
+	defer upstream.Close()
+
+	raw, _, err := writer.(http.Hijacker).Hijack()
+	if err != nil {
+		http.Error(writer, "no upstream", http.StatusServiceUnavailable)
+
+		return err
+	}
+
+	defer raw.Close()
+
+	if _, err = raw.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		return err
+	}
+
+	done := make(chan struct{}, 2)
+
+	go func() {
+		io.Copy(upstream, raw)
+		done <- struct{}{}
+	}()
+
+	go func() {
+		io.Copy(raw, upstream)
+		done <- struct{}{}
+	}()
+
+	<-done
+
+	return nil
+}
+
 func handshake(writer http.ResponseWriter, config *tls.Config) (net.Conn, error) {
 	raw, _, err := writer.(http.Hijacker).Hijack()
 	if err != nil {
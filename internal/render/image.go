@@ -0,0 +1,40 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+var ErrNotImage = errors.New("body is not a supported image")
+
+type ImageInfo struct {
+	Format   string `json:"format"`
+	MimeType string `json:"mime_type"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Size     int    `json:"size"`
+}
+
+// Image reads only the header, without decoding the pixels.
+func Image(body *Body) (*ImageInfo, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(body.Content))
+	if err != nil {
+		return nil, errors.Join(ErrNotImage, err)
+	}
+
+	return &ImageInfo{
+		Format:   format,
+		MimeType: "image/" + format,
+		Width:    config.Width,
+		Height:   config.Height,
+		Size:     len(body.Content),
+	}, nil
+}
@@ -0,0 +1,130 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+	voidElements = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+		"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+	}
+)
+
+// indentMarkup puts every element on its own line; HTML is read leniently.
+func indentMarkup(text []byte, html bool) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(text))
+	if html {
+		decoder.Strict = false
+		decoder.Entity = xml.HTMLEntity
+	}
+
+	// The text is already UTF-8, whatever the declaration says.
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	buffer := &bytes.Buffer{}
+	depth := 0
+	inline := false
+
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			return buffer.Bytes(), nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			name := qualifiedName(token.Name)
+
+			newline(buffer, depth)
+			buffer.WriteString("<" + name)
+
+			for _, attr := range token.Attr {
+				buffer.WriteString(" " + qualifiedName(attr.Name) + `="`)
+				attrEscaper.WriteString(buffer, attr.Value)
+				buffer.WriteString(`"`)
+			}
+
+			buffer.WriteString(">")
+
+			inline = !(html && voidElements[strings.ToLower(name)])
+			if inline {
+				depth++
+			}
+		case xml.EndElement:
+			name := qualifiedName(token.Name)
+			if html && voidElements[strings.ToLower(name)] {
+				continue
+			}
+
+			depth = max(depth-1, 0)
+
+			if !inline {
+				newline(buffer, depth)
+			}
+
+			buffer.WriteString("</" + name + ">")
+
+			inline = false
+		case xml.CharData:
+			trimmed := strings.TrimSpace(string(token))
+			if trimmed == "" {
+				continue
+			}
+
+			if !inline {
+				newline(buffer, depth)
+			}
+
+			textEscaper.WriteString(buffer, trimmed)
+		case xml.Comment:
+			newline(buffer, depth)
+			buffer.WriteString("<!--" + string(token) + "-->")
+
+			inline = false
+		case xml.ProcInst:
+			newline(buffer, depth)
+			buffer.WriteString("<?" + token.Target)
+			if len(token.Inst) > 0 {
+				buffer.WriteString(" " + string(token.Inst))
+			}
+			buffer.WriteString("?>")
+
+			inline = false
+		case xml.Directive:
+			newline(buffer, depth)
+			buffer.WriteString("<!" + string(token) + ">")
+
+			inline = false
+		}
+	}
+}
+
+func newline(buffer *bytes.Buffer, depth int) {
+	if buffer.Len() > 0 {
+		buffer.WriteString("\n")
+	}
+
+	buffer.WriteString(strings.Repeat("  ", depth))
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
@@ -0,0 +1,93 @@
+package render
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"proxy/internal/network"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ModeRaw    = "raw"
+	ModePretty = "pretty"
+	ModeHex    = "hex"
+	ModeImage  = "image"
+)
+
+var (
+	ErrUnknownMode = errors.New("unknown render mode")
+	ErrNotText     = errors.New("body is not text")
+)
+
+type Body struct {
+	Header  http.Header
+	Content []byte
+}
+
+func (body *Body) MimeType() string {
+	return network.DetectMIME(body.Header, body.Content)
+}
+
+func (body *Body) ContentType() string {
+	if contentType := body.Header.Get("Content-Type"); contentType != "" {
+		return contentType
+	}
+
+	if mimeType := body.MimeType(); mimeType != "" {
+		return mimeType
+	}
+
+	return "application/octet-stream"
+}
+
+func (body *Body) Text() ([]byte, error) {
+	text, err := network.ToUTF8(body.Content, network.Charset(body.Header, body.Content))
+	if err != nil {
+		return nil, err
+	}
+
+	if !utf8.Valid(text) {
+		return nil, ErrNotText
+	}
+
+	return text, nil
+}
+
+// Pretty indents JSON, XML and HTML and returns other text unchanged.
+func Pretty(body *Body) ([]byte, error) {
+	mimeType := body.MimeType()
+	if !network.IsText(mimeType) && !utf8.Valid(body.Content) {
+		return nil, ErrNotText
+	}
+
+	text, err := body.Text()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasSuffix(mimeType, "json"):
+		buffer := &bytes.Buffer{}
+		if err := json.Indent(buffer, text, "", "  "); err == nil {
+			return buffer.Bytes(), nil
+		}
+	case mimeType == "text/html":
+		if indented, err := indentMarkup(text, true); err == nil {
+			return indented, nil
+		}
+	case strings.HasSuffix(mimeType, "xml"):
+		if indented, err := indentMarkup(text, false); err == nil {
+			return indented, nil
+		}
+	}
+
+	return text, nil
+}
+
+func Hex(body *Body) []byte {
+	return []byte(hex.Dump(body.Content))
+}
@@ -0,0 +1,112 @@
+package scan
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"strings"
+)
+
+const (
+	CORSAttacker       = "attacker_origin"
+	CORSNull           = "null_origin"
+	CORSPrefix         = "prefix_match"
+	CORSSuffix         = "suffix_match"
+	CORSUnescapedDot   = "unescaped_dot"
+	CORSSubdomain      = "subdomain"
+	CORSInsecureScheme = "insecure_scheme"
+	CORSCustom         = "custom"
+
+	IssueCORS = "cors_misconfiguration"
+
+	attackerDomain = "cors-attacker.com"
+)
+
+type OriginProbe struct {
+	Name   string
+	Origin string
+}
+
+// CORSProbes builds the origins to send to target: an unrelated site, the null origin,
+// and lookalikes of the target's own origin that broken allow-list checks tend to accept.
+func CORSProbes(target *url.URL, custom []string) []*OriginProbe {
+	scheme := strings.ToLower(target.Scheme)
+	host := strings.ToLower(target.Host)
+	hostname := strings.ToLower(target.Hostname())
+
+	probes := []*OriginProbe{
+		{Name: CORSAttacker, Origin: scheme + "://" + attackerDomain},
+		{Name: CORSNull, Origin: "null"},
+		{Name: CORSPrefix, Origin: scheme + "://" + hostname + "." + attackerDomain},
+		{Name: CORSSuffix, Origin: scheme + "://" + "attacker" + host},
+		{Name: CORSSubdomain, Origin: scheme + "://" + "attacker." + host},
+	}
+
+	if dot := strings.Index(host, "."); dot > 0 && strings.Count(hostname, ".") > 1 && net.ParseIP(hostname) == nil {
+		probes = append(probes, &OriginProbe{
+			Name:   CORSUnescapedDot,
+			Origin: scheme + "://" + host[:dot] + "x" + host[dot+1:],
+		})
+	}
+
+	if scheme == "https" {
+		probes = append(probes, &OriginProbe{Name: CORSInsecureScheme, Origin: "http://" + host})
+	}
+
+	for _, origin := range custom {
+		probes = append(probes, &OriginProbe{Name: CORSCustom, Origin: origin})
+	}
+
+	return probes
+}
+
+// CheckCORS reports the severity and a description when the response lets the probe origin
+// read it. An empty severity means the policy rejected the origin.
+func CheckCORS(probe *OriginProbe, response *network.HTTPResponse) (string, string) {
+	allowOrigin := strings.TrimSpace(response.Headers.Get("Access-Control-Allow-Origin"))
+	credentials := strings.EqualFold(strings.TrimSpace(response.Headers.Get("Access-Control-Allow-Credentials")), "true")
+
+	if allowOrigin == "*" {
+		if probe.Name != CORSAttacker {
+			return "", ""
+		}
+
+		return models.SeverityLow, "any origin is allowed to read responses"
+	}
+
+	if allowOrigin != probe.Origin {
+		return "", ""
+	}
+
+	finding := fmt.Sprintf("origin %s is trusted", probe.Origin)
+	if credentials {
+		finding += " with credentials"
+	}
+
+	switch probe.Name {
+	case CORSSubdomain, CORSInsecureScheme:
+		if credentials {
+			return models.SeverityMedium, finding
+		}
+
+		return models.SeverityLow, finding
+	default:
+		if credentials {
+			return models.SeverityHigh, finding
+		}
+
+		return models.SeverityMedium, finding
+	}
+}
+
+func CORSEvidence(response *network.HTTPResponse) string {
+	evidence := "Access-Control-Allow-Origin: " + response.Headers.Get("Access-Control-Allow-Origin")
+
+	if credentials := response.Headers.Get("Access-Control-Allow-Credentials"); credentials != "" {
+		evidence += "; Access-Control-Allow-Credentials: " + credentials
+	}
+
+	return evidence
+}
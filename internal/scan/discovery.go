@@ -0,0 +1,140 @@
+package scan
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"strings"
+)
+
+const (
+	tokenBytes = 6
+
+	// lengthTolerance lets dynamic error pages differ by up to 1/lengthTolerance of their size.
+	lengthTolerance = 50
+)
+
+// Fingerprint describes how a server answers a path, with the requested path token removed
+// from the body and redirect target so that reflected paths do not hide a wildcard response.
+type Fingerprint struct {
+	Status   int
+	Length   int
+	Location string
+	Hash     [sha256.Size]byte
+}
+
+func NewFingerprint(response *network.HTTPResponse, token string) *Fingerprint {
+	body := string(response.Body)
+	location := response.Headers.Get("Location")
+
+	if token != "" {
+		body = strings.ReplaceAll(body, token, "")
+		location = strings.ReplaceAll(location, token, "{}")
+	}
+
+	return &Fingerprint{
+		Status:   response.Code,
+		Length:   len(body),
+		Location: location,
+		Hash:     sha256.Sum256([]byte(body)),
+	}
+}
+
+func (fingerprint *Fingerprint) Matches(other *Fingerprint) bool {
+	if fingerprint.Status != other.Status || fingerprint.Location != other.Location {
+		return false
+	}
+
+	if fingerprint.Hash == other.Hash {
+		return true
+	}
+
+	tolerance := fingerprint.Length / lengthTolerance
+	delta := fingerprint.Length - other.Length
+
+	return delta <= tolerance && delta >= -tolerance
+}
+
+func RandomToken() string {
+	token := make([]byte, tokenBytes)
+	rand.Read(token)
+
+	return hex.EncodeToString(token)
+}
+
+func Candidates(words, extensions []string) []string {
+	candidates := make([]string, 0, len(words)*(len(extensions)+1))
+	seen := make(map[string]bool)
+
+	for _, word := range words {
+		word = strings.Trim(strings.TrimSpace(word), "/")
+		if word == "" {
+			continue
+		}
+
+		for _, extension := range append([]string{""}, extensions...) {
+			if extension != "" && !strings.HasPrefix(extension, ".") {
+				extension = "." + extension
+			}
+
+			candidate := word + extension
+			if !seen[candidate] {
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	return candidates
+}
+
+// Classify returns an empty class for responses that mean the path does not exist.
+func Classify(status int) string {
+	switch {
+	case status == http.StatusNotFound || status == http.StatusGone:
+		return ""
+	case status >= 200 && status < 300:
+		return models.ClassFound
+	case status >= 300 && status < 400:
+		return models.ClassRedirect
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return models.ClassForbidden
+	case status >= 500:
+		return models.ClassError
+	default:
+		return models.ClassOther
+	}
+}
+
+func BasePath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	return path
+}
+
+// Probe turns a stored request into a bodiless GET for path, keeping its host, headers and cookies.
+func Probe(source *network.HTTPRequest, path string) *network.HTTPRequest {
+	probe := source.Clone()
+	probe.ID = ""
+	probe.ParentID = ""
+	probe.Method = http.MethodGet
+	probe.Path = path
+	probe.GetParams = nil
+	probe.PostParams = nil
+	probe.Body = nil
+
+	for _, header := range []string{"Content-Type", "Content-Length", "Content-Encoding", "Transfer-Encoding"} {
+		probe.Header.Del(header)
+	}
+
+	return probe
+}
@@ -0,0 +1,50 @@
+package scan
+
+import (
+	"net/url"
+	"proxy/internal/insertion"
+	"proxy/internal/network"
+	"regexp"
+	"strings"
+)
+
+var redirectParamName = regexp.MustCompile(`(?i)(url|uri|redirect|redir|return|next|dest|destination|continue|` +
+	`goto|target|callback|forward|out|link|image|img|feed|host|domain|site|proxy|fetch|load|src|href)`)
+
+// URLParams finds the insertion points of request that hold URLs or paths, or are named like
+// they would.
+func URLParams(request *network.HTTPRequest) []*insertion.Point {
+	params := make([]*insertion.Point, 0)
+
+	for _, point := range insertion.Points(request) {
+		if point.Location == insertion.LocationFilename {
+			continue
+		}
+
+		name := point.Name
+		if point.Location == insertion.LocationJSON || point.Location == insertion.LocationXML {
+			name = name[strings.LastIndex(name, "/")+1:]
+		}
+
+		if isURLLike(name, point.Value) {
+			params = append(params, point)
+		}
+	}
+
+	return params
+}
+
+func isURLLike(name, value string) bool {
+	value = strings.TrimSpace(value)
+
+	lower := strings.ToLower(value)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(value, "//") {
+		return true
+	}
+
+	if decoded, err := url.QueryUnescape(lower); err == nil && decoded != lower {
+		return isURLLike(name, decoded)
+	}
+
+	return redirectParamName.MatchString(name) && (value == "" || strings.HasPrefix(value, "/"))
+}
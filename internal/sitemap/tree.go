@@ -0,0 +1,214 @@
+package sitemap
+
+import (
+	"net/url"
+	"proxy/internal/network"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Node struct {
+	Name         string         `json:"name"`
+	Path         string         `json:"path"`
+	Count        int            `json:"count"`
+	Total        int            `json:"total"`
+	Methods      map[string]int `json:"methods,omitempty"`
+	Params       []string       `json:"params,omitempty"`
+	StatusCodes  map[int]int    `json:"status_codes,omitempty"`
+	ContentTypes map[string]int `json:"content_types,omitempty"`
+	Discovered   bool           `json:"discovered,omitempty"`
+	Children     []*Node        `json:"children,omitempty"`
+}
+
+type node struct {
+	name         string
+	path         string
+	count        int
+	methods      map[string]int
+	params       map[string]bool
+	statusCodes  map[int]int
+	contentTypes map[string]int
+	discovered   bool
+	children     map[string]*node
+}
+
+func newNode(name, path string, discovered bool) *node {
+	return &node{
+		name:         name,
+		path:         path,
+		methods:      make(map[string]int),
+		params:       make(map[string]bool),
+		statusCodes:  make(map[int]int),
+		contentTypes: make(map[string]int),
+		discovered:   discovered,
+		children:     make(map[string]*node),
+	}
+}
+
+// Tree groups traffic by origin and path segment. Every request is counted once,
+// so replaying the whole history over a live tree does not change it.
+type Tree struct {
+	mu    sync.Mutex
+	seen  map[string]bool
+	hosts map[string]*node
+}
+
+func New() *Tree {
+	return &Tree{
+		seen:  make(map[string]bool),
+		hosts: make(map[string]*node),
+	}
+}
+
+func Origin(target *url.URL) string {
+	origin := &url.URL{Scheme: strings.ToLower(target.Scheme), Host: strings.ToLower(target.Host)}
+
+	return origin.String()
+}
+
+func (tree *Tree) Add(request *network.HTTPRequest, response *network.HTTPResponse) {
+	target, err := url.Parse(request.URL())
+	if err != nil {
+		return
+	}
+
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+
+	if request.ID != "" {
+		if tree.seen[request.ID] {
+			return
+		}
+
+		tree.seen[request.ID] = true
+	}
+
+	leaf := tree.walk(target, false)
+	leaf.discovered = false
+	leaf.count++
+	leaf.methods[strings.ToUpper(request.Method)]++
+
+	for name := range request.GetParams {
+		leaf.params[name] = true
+	}
+
+	for name := range request.PostParams {
+		leaf.params[name] = true
+	}
+
+	if response == nil {
+		return
+	}
+
+	leaf.statusCodes[response.Code]++
+
+	contentType := response.ContentType()
+	if contentType != "" {
+		leaf.contentTypes[contentType]++
+	}
+
+	for _, link := range Links(target, contentType, response.Body) {
+		tree.walk(link, true)
+	}
+}
+
+func (tree *Tree) walk(target *url.URL, discovered bool) *node {
+	origin := Origin(target)
+
+	current, ok := tree.hosts[origin]
+	if !ok {
+		current = newNode(origin, "/", discovered)
+		tree.hosts[origin] = current
+	}
+
+	path := ""
+
+	for _, segment := range strings.Split(target.Path, "/") {
+		if segment == "" {
+			continue
+		}
+
+		path += "/" + segment
+
+		child, ok := current.children[segment]
+		if !ok {
+			child = newNode(segment, path, discovered)
+			current.children[segment] = child
+		}
+
+		current = child
+	}
+
+	return current
+}
+
+// Snapshot copies the tree for the given origin, or for all origins when it is empty.
+// Origins can be given with or without a scheme.
+func (tree *Tree) Snapshot(origin string) []*Node {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+
+	origin = strings.ToLower(origin)
+	nodes := make([]*Node, 0, len(tree.hosts))
+
+	for name, host := range tree.hosts {
+		if origin != "" && name != origin && !strings.HasSuffix(name, "://"+origin) {
+			continue
+		}
+
+		nodes = append(nodes, host.snapshot())
+	}
+
+	sortNodes(nodes)
+
+	return nodes
+}
+
+func (current *node) snapshot() *Node {
+	result := &Node{
+		Name:         current.name,
+		Path:         current.path,
+		Count:        current.count,
+		Total:        current.count,
+		Methods:      copyMap(current.methods),
+		StatusCodes:  copyMap(current.statusCodes),
+		ContentTypes: copyMap(current.contentTypes),
+		Discovered:   current.discovered,
+	}
+
+	for name := range current.params {
+		result.Params = append(result.Params, name)
+	}
+
+	sort.Strings(result.Params)
+
+	for _, child := range current.children {
+		snapshot := child.snapshot()
+		result.Total += snapshot.Total
+		result.Children = append(result.Children, snapshot)
+	}
+
+	sortNodes(result.Children)
+
+	return result
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+func copyMap[K comparable](values map[K]int) map[K]int {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make(map[K]int, len(values))
+	for key, value := range values {
+		result[key] = value
+	}
+
+	return result
+}
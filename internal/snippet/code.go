@@ -0,0 +1,166 @@
+package snippet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func renderGo(request *snippetRequest) string {
+	builder := &strings.Builder{}
+
+	builder.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+
+	body := "nil"
+	if len(request.body) > 0 {
+		builder.WriteString("\t\"strings\"\n")
+		body = "strings.NewReader(" + strconv.Quote(string(request.body)) + ")"
+	}
+
+	builder.WriteString(")\n\nfunc main() {\n")
+	fmt.Fprintf(builder, "\treq, err := http.NewRequest(%s, %s, %s)\n",
+		strconv.Quote(request.method), strconv.Quote(request.url), body)
+	builder.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n\n")
+
+	for _, header := range request.headers {
+		fmt.Fprintf(builder, "\treq.Header.Add(%s, %s)\n", strconv.Quote(header.name), strconv.Quote(header.value))
+	}
+
+	for _, cookie := range request.cookies {
+		fmt.Fprintf(builder, "\treq.AddCookie(&http.Cookie{Name: %s, Value: %s})\n",
+			strconv.Quote(cookie.Name), strconv.Quote(cookie.Value))
+	}
+
+	if len(request.headers) > 0 || len(request.cookies) > 0 {
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("\tresp, err := http.DefaultClient.Do(req)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n" +
+		"\tdefer resp.Body.Close()\n\n" +
+		"\tdata, err := io.ReadAll(resp.Body)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n\n" +
+		"\tfmt.Println(resp.Status)\n\tfmt.Println(string(data))\n}\n")
+
+	return builder.String()
+}
+
+func renderPython(request *snippetRequest) string {
+	builder := &strings.Builder{}
+
+	builder.WriteString("import requests\n\n")
+	builder.WriteString("url = " + jsString(request.url) + "\n")
+
+	arguments := []string{"headers=headers"}
+
+	builder.WriteString("headers = {\n")
+
+	for _, header := range joinHeaders(request.headers) {
+		builder.WriteString("    " + jsString(header.name) + ": " + jsString(header.value) + ",\n")
+	}
+
+	builder.WriteString("}\n")
+
+	if len(request.cookies) > 0 {
+		builder.WriteString("cookies = {\n")
+
+		for _, cookie := range request.cookies {
+			builder.WriteString("    " + jsString(cookie.Name) + ": " + jsString(cookie.Value) + ",\n")
+		}
+
+		builder.WriteString("}\n")
+
+		arguments = append(arguments, "cookies=cookies")
+	}
+
+	if len(request.body) > 0 {
+		if request.binary {
+			builder.WriteString("data = " + pythonBytes(request.body) + "\n")
+		} else {
+			builder.WriteString("data = " + jsString(string(request.body)) + ".encode()\n")
+		}
+
+		arguments = append(arguments, "data=data")
+	}
+
+	fmt.Fprintf(builder, "\nresponse = requests.request(%s, url, %s)\n", jsString(request.method),
+		strings.Join(arguments, ", "))
+	builder.WriteString("print(response.status_code)\nprint(response.text)\n")
+
+	return builder.String()
+}
+
+func pythonBytes(body []byte) string {
+	builder := &strings.Builder{}
+
+	builder.WriteString(`b"`)
+
+	for _, b := range body {
+		switch {
+		case b == '"' || b == '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(b)
+		case b >= 0x20 && b < 0x7f:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(builder, `\x%02x`, b)
+		}
+	}
+
+	builder.WriteString(`"`)
+
+	return builder.String()
+}
+
+func renderFetch(request *snippetRequest) string {
+	builder := &strings.Builder{}
+
+	builder.WriteString("const response = await fetch(" + jsString(request.url) + ", {\n")
+	builder.WriteString("  method: " + jsString(request.method) + ",\n")
+	builder.WriteString("  headers: {\n")
+
+	for _, header := range joinHeaders(request.headers) {
+		builder.WriteString("    " + jsString(header.name) + ": " + jsString(header.value) + ",\n")
+	}
+
+	if len(request.cookies) > 0 {
+		builder.WriteString("    \"Cookie\": " + jsString(request.cookieHeader()) + ",\n")
+	}
+
+	builder.WriteString("  },\n")
+
+	if len(request.body) > 0 {
+		if request.binary {
+			values := make([]string, 0, len(request.body))
+			for _, b := range request.body {
+				values = append(values, strconv.Itoa(int(b)))
+			}
+
+			builder.WriteString("  body: new Uint8Array([" + strings.Join(values, ", ") + "]),\n")
+		} else {
+			builder.WriteString("  body: " + jsString(string(request.body)) + ",\n")
+		}
+	}
+
+	builder.WriteString("});\n\nconsole.log(response.status);\nconsole.log(await response.text());\n")
+
+	return builder.String()
+}
+
+// joinHeaders folds repeated headers into one comma-separated value for dictionary-based clients.
+func joinHeaders(headers []header) []header {
+	joined := make([]header, 0, len(headers))
+	positions := make(map[string]int)
+
+	for _, h := range headers {
+		position, ok := positions[h.name]
+		if !ok {
+			positions[h.name] = len(joined)
+			joined = append(joined, h)
+
+			continue
+		}
+
+		joined[position].value += ", " + h.value
+	}
+
+	return joined
+}
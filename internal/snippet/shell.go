@@ -0,0 +1,82 @@
+package snippet
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// binaryPipe feeds a binary body through stdin, since shell arguments cannot carry NUL bytes.
+func binaryPipe(body []byte) string {
+	return "printf '%s' " + shellQuote(base64.StdEncoding.EncodeToString(body)) + " | base64 -d | "
+}
+
+func renderCurl(request *snippetRequest) string {
+	builder := &strings.Builder{}
+
+	if request.binary {
+		builder.WriteString(binaryPipe(request.body))
+	}
+
+	builder.WriteString("curl -X " + shellQuote(request.method) + " " + shellQuote(request.url))
+
+	for _, header := range request.headers {
+		builder.WriteString(" \\\n  -H " + shellQuote(header.name+": "+header.value))
+	}
+
+	if len(request.cookies) > 0 {
+		builder.WriteString(" \\\n  -b " + shellQuote(request.cookieHeader()))
+	}
+
+	switch {
+	case request.binary:
+		builder.WriteString(" \\\n  --data-binary @-")
+	case len(request.body) > 0:
+		builder.WriteString(" \\\n  --data-raw " + shellQuote(string(request.body)))
+	}
+
+	return builder.String()
+}
+
+func renderHTTPie(request *snippetRequest) string {
+	builder := &strings.Builder{}
+
+	if request.binary {
+		builder.WriteString(binaryPipe(request.body))
+	}
+
+	builder.WriteString("http")
+
+	if !request.binary {
+		builder.WriteString(" --ignore-stdin")
+	}
+
+	if len(request.body) > 0 && !request.binary {
+		builder.WriteString(" --raw " + shellQuote(string(request.body)))
+	}
+
+	builder.WriteString(" " + shellQuote(request.method) + " " + shellQuote(request.url))
+
+	for _, header := range request.headers {
+		if header.value == "" {
+			builder.WriteString(" \\\n  " + shellQuote(header.name+";"))
+
+			continue
+		}
+
+		builder.WriteString(" \\\n  " + shellQuote(header.name+":"+header.value))
+	}
+
+	if len(request.cookies) > 0 {
+		builder.WriteString(" \\\n  " + shellQuote("Cookie:"+request.cookieHeader()))
+	}
+
+	return builder.String()
+}
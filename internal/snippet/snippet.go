@@ -0,0 +1,117 @@
+package snippet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"proxy/internal/network"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	FormatCurl   = "curl"
+	FormatHTTPie = "httpie"
+	FormatGo     = "go"
+	FormatPython = "python"
+	FormatFetch  = "fetch"
+	FormatRaw    = "raw"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+type header struct {
+	name  string
+	value string
+}
+
+// snippetRequest is the part of a stored request every format renders.
+type snippetRequest struct {
+	method  string
+	url     string
+	headers []header
+	cookies []*http.Cookie
+	body    []byte
+	binary  bool
+}
+
+func Render(req *network.HTTPRequest, format string) (string, error) {
+	if format == FormatRaw {
+		return string(req.Raw()), nil
+	}
+
+	request := newSnippetRequest(req)
+
+	switch format {
+	case FormatCurl:
+		return renderCurl(request), nil
+	case FormatHTTPie:
+		return renderHTTPie(request), nil
+	case FormatGo:
+		return renderGo(request), nil
+	case FormatPython:
+		return renderPython(request), nil
+	case FormatFetch:
+		return renderFetch(request), nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+func newSnippetRequest(req *network.HTTPRequest) *snippetRequest {
+	request := &snippetRequest{
+		method:  req.Method,
+		url:     req.URL(),
+		cookies: req.Cookies,
+		body:    req.Body,
+	}
+
+	if len(req.PostParams) > 0 {
+		request.body = []byte(req.PostParams.Encode())
+	}
+
+	request.binary = !utf8.Valid(request.body) || bytes.IndexByte(request.body, 0) != -1
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		canonical := http.CanonicalHeaderKey(name)
+		if canonical == "Content-Length" || canonical == "Cookie" && len(req.Cookies) > 0 {
+			continue
+		}
+
+		for _, value := range req.Header[name] {
+			request.headers = append(request.headers, header{name: name, value: value})
+		}
+	}
+
+	return request
+}
+
+func (request *snippetRequest) cookieHeader() string {
+	cookies := make([]string, 0, len(request.cookies))
+
+	for _, cookie := range request.cookies {
+		cookies = append(cookies, cookie.Name+"="+cookie.Value)
+	}
+
+	return strings.Join(cookies, "; ")
+}
+
+// jsString quotes s as a JSON string, which is also a valid JavaScript and Python literal.
+func jsString(s string) string {
+	buffer := &bytes.Buffer{}
+
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
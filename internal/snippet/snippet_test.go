@@ -0,0 +1,103 @@
+package snippet
+
+import (
+	"net/http"
+	"proxy/internal/network"
+	"strings"
+	"testing"
+)
+
+func TestRenderBodies(t *testing.T) {
+	tests := []struct {
+		name string
+		body []byte
+		want map[string]string
+	}{
+		{
+			name: "single quote",
+			body: []byte("it's"),
+			want: map[string]string{
+				FormatCurl:   `--data-raw 'it'\''s'`,
+				FormatHTTPie: `--raw 'it'\''s'`,
+				FormatGo:     `strings.NewReader("it's")`,
+				FormatPython: `data = "it's".encode()`,
+				FormatFetch:  `body: "it's",`,
+			},
+		},
+		{
+			name: "newline",
+			body: []byte("a\nb"),
+			want: map[string]string{
+				FormatCurl:   "--data-raw 'a\nb'",
+				FormatHTTPie: "--raw 'a\nb'",
+				FormatGo:     `strings.NewReader("a\nb")`,
+				FormatPython: `data = "a\nb".encode()`,
+				FormatFetch:  `body: "a\nb",`,
+			},
+		},
+		{
+			name: "dollar",
+			body: []byte("$HOME $(id)"),
+			want: map[string]string{
+				FormatCurl:   `--data-raw '$HOME $(id)'`,
+				FormatHTTPie: `--raw '$HOME $(id)'`,
+				FormatGo:     `strings.NewReader("$HOME $(id)")`,
+				FormatPython: `data = "$HOME $(id)".encode()`,
+				FormatFetch:  `body: "$HOME $(id)",`,
+			},
+		},
+		{
+			name: "backslash",
+			body: []byte(`a\b"c`),
+			want: map[string]string{
+				FormatCurl:   `--data-raw 'a\b"c'`,
+				FormatHTTPie: `--raw 'a\b"c'`,
+				FormatGo:     `strings.NewReader("a\\b\"c")`,
+				FormatPython: `data = "a\\b\"c".encode()`,
+				FormatFetch:  `body: "a\\b\"c",`,
+			},
+		},
+		{
+			name: "binary",
+			body: []byte{0x00, 0xff, '"'},
+			want: map[string]string{
+				FormatCurl:   "printf '%s' 'AP8i' | base64 -d | curl -X 'POST' 'http://example.com/' \\\n  --data-binary @-",
+				FormatHTTPie: "printf '%s' 'AP8i' | base64 -d | http 'POST' 'http://example.com/'",
+				FormatGo:     `strings.NewReader("\x00\xff\"")`,
+				FormatPython: `data = b"\x00\xff\""`,
+				FormatFetch:  `body: new Uint8Array([0, 255, 34]),`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		for format, want := range test.want {
+			t.Run(test.name+"/"+format, func(t *testing.T) {
+				request := &network.HTTPRequest{
+					Method: "POST",
+					Scheme: "http",
+					Host:   "example.com",
+					Path:   "/",
+					Header: http.Header{},
+					Body:   test.body,
+				}
+
+				got, err := Render(request, format)
+				if err != nil {
+					t.Fatalf("Render: %v", err)
+				}
+
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() = %q, want it to contain %q", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	_, err := Render(&network.HTTPRequest{Header: http.Header{}}, "perl")
+	if err != ErrUnknownFormat {
+		t.Errorf("Render() error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
@@ -0,0 +1,83 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"log"
+	"net/http"
+	"proxy/internal/render"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/usecases"
+)
+
+func (h *Handler) GetBody(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+	query := request.URL.Query()
+
+	part := query.Get("part")
+	if part == "" {
+		part = models.BodyPartResponse
+	}
+
+	body, err := usecases.MessageBody(h.storage, vars["id"], part)
+	switch {
+	case errors.Is(err, models.ErrBadBodyPart):
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	case errors.Is(err, models.ErrNotFound):
+		http.Error(writer, "request not found", http.StatusNotFound)
+
+		return
+	case err != nil:
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	var (
+		contentType = "text/plain; charset=utf-8"
+		content     []byte
+	)
+
+	switch query.Get("mode") {
+	case "", render.ModeRaw:
+		contentType, content = body.ContentType(), body.Content
+	case render.ModePretty:
+		content, err = render.Pretty(body)
+	case render.ModeHex:
+		content = render.Hex(body)
+	case render.ModeImage:
+		info, err := render.Image(body)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		SendOkResponse(writer, info)
+
+		return
+	default:
+		http.Error(writer, render.ErrUnknownMode.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	// A recorded page must not run scripts on the origin of the API.
+	writer.Header().Set("Content-Type", contentType)
+	writer.Header().Set("Content-Security-Policy", "sandbox")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(http.StatusOK)
+
+	_, err = writer.Write(content)
+	if err != nil {
+		log.Println("Something went wrong while writing body", err)
+	}
+}
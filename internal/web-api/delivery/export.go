@@ -0,0 +1,92 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"proxy/internal/har"
+	"proxy/internal/snippet"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/usecases"
+	"strings"
+)
+
+func (h *Handler) ExportHAR(writer http.ResponseWriter, request *http.Request) {
+	ids, err := h.selectIDs(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	transactions, err := usecases.LoadTransactions(h.storage, ids)
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(writer, "request not found", http.StatusNotFound)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	writer.Header().Set("Content-Disposition", `attachment; filename="traffic.har"`)
+
+	SendOkResponse(writer, har.Export(transactions))
+}
+
+func (h *Handler) selectIDs(request *http.Request) ([]string, error) {
+	if value := request.URL.Query().Get("ids"); value != "" {
+		ids := make([]string, 0)
+
+		for _, id := range strings.Split(value, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+
+		return ids, nil
+	}
+
+	filter, err := parseRequestsFilter(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return usecases.CollectIDs(h.storage, filter)
+}
+
+func (h *Handler) ExportRequest(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	req, err := h.storage.GetRequest(vars["id"])
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(writer, "request not found", http.StatusNotFound)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	format := request.URL.Query().Get("format")
+	if format == "" {
+		format = snippet.FormatCurl
+	}
+
+	code, err := snippet.Render(req, format)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte(code))
+}
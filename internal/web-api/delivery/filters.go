@@ -0,0 +1,132 @@
+package delivery
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"proxy/internal/web-api/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func parseRequestsFilter(request *http.Request) (*models.RequestsFilter, error) {
+	query := request.URL.Query()
+
+	filter := &models.RequestsFilter{
+		Method:      query.Get("method"),
+		Host:        query.Get("host"),
+		PathGlob:    query.Get("path"),
+		ContentType: query.Get("content_type"),
+		ParentID:    query.Get("parent"),
+		SortBy:      query.Get("sort"),
+		Cursor:      query.Get("cursor"),
+		Limit:       models.DefaultPageLimit,
+	}
+
+	if filter.PathGlob != "" {
+		if _, err := path.Match(filter.PathGlob, ""); err != nil {
+			return nil, fmt.Errorf("invalid path pattern: %w", err)
+		}
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = models.SortByID
+	} else if !models.IsValidSort(filter.SortBy) {
+		return nil, fmt.Errorf("invalid sort field %q", filter.SortBy)
+	}
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return nil, fmt.Errorf("invalid order %q", query.Get("order"))
+	}
+
+	var err error
+
+	if status := query.Get("status"); status != "" {
+		filter.StatusCode, err = strconv.Atoi(status)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status %q", status)
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit <= 0 {
+			return nil, fmt.Errorf("invalid limit %q", limit)
+		}
+
+		filter.Limit = min(filter.Limit, models.MaxPageLimit)
+	}
+
+	if from := query.Get("from"); from != "" {
+		filter.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from time %q", from)
+		}
+	}
+
+	if to := query.Get("to"); to != "" {
+		filter.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to time %q", to)
+		}
+	}
+
+	return filter, nil
+}
+
+func parseSearchQuery(request *http.Request) (*models.SearchQuery, error) {
+	query := request.URL.Query()
+
+	searchQuery := &models.SearchQuery{
+		Query: query.Get("q"),
+		Mode:  query.Get("mode"),
+		Limit: models.DefaultSearchLimit,
+	}
+
+	if searchQuery.Query == "" {
+		return nil, fmt.Errorf("empty search query")
+	}
+
+	switch searchQuery.Mode {
+	case "":
+		searchQuery.Mode = models.SearchModeLiteral
+	case models.SearchModeLiteral, models.SearchModeRegex:
+	default:
+		return nil, fmt.Errorf("invalid search mode %q", searchQuery.Mode)
+	}
+
+	var err error
+
+	if ignoreCase := query.Get("ignore_case"); ignoreCase != "" {
+		searchQuery.IgnoreCase, err = strconv.ParseBool(ignoreCase)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore_case %q", ignoreCase)
+		}
+	}
+
+	for _, field := range query["in"] {
+		for _, name := range strings.Split(field, ",") {
+			if !models.IsValidSearchField(name) {
+				return nil, fmt.Errorf("invalid search field %q", name)
+			}
+
+			searchQuery.Fields = append(searchQuery.Fields, name)
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		searchQuery.Limit, err = strconv.Atoi(limit)
+		if err != nil || searchQuery.Limit <= 0 {
+			return nil, fmt.Errorf("invalid limit %q", limit)
+		}
+
+		searchQuery.Limit = min(searchQuery.Limit, models.MaxSearchLimit)
+	}
+
+	return searchQuery, nil
+}
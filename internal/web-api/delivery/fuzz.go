@@ -0,0 +1,112 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"proxy/internal/web-api/models"
+)
+
+func attackErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrBadAttack):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrOutOfScope):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrAttackNotFound), errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *Handler) StartAttack(writer http.ResponseWriter, request *http.Request) {
+	config := &models.AttackConfig{}
+
+	err := json.NewDecoder(request.Body).Decode(config)
+	if err != nil {
+		http.Error(writer, models.ErrBadAttack.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	attack, err := h.fuzzer.Start(config)
+	if err != nil {
+		http.Error(writer, err.Error(), attackErrorStatus(err))
+
+		return
+	}
+
+	SendOkResponse(writer, attack)
+}
+
+func (h *Handler) GetAttacks(writer http.ResponseWriter, request *http.Request) {
+	SendOkResponse(writer, h.fuzzer.List())
+}
+
+func (h *Handler) GetAttack(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	attack, err := h.fuzzer.Get(vars["id"])
+	if err != nil {
+		http.Error(writer, err.Error(), attackErrorStatus(err))
+
+		return
+	}
+
+	SendOkResponse(writer, attack)
+}
+
+func (h *Handler) StopAttack(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	err := h.fuzzer.Stop(vars["id"])
+	if err != nil {
+		http.Error(writer, err.Error(), attackErrorStatus(err))
+
+		return
+	}
+
+	attack, err := h.fuzzer.Get(vars["id"])
+	if err != nil {
+		http.Error(writer, err.Error(), attackErrorStatus(err))
+
+		return
+	}
+
+	SendOkResponse(writer, attack)
+}
+
+func (h *Handler) DeleteAttack(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	err := h.fuzzer.Delete(vars["id"])
+	if err != nil {
+		http.Error(writer, err.Error(), attackErrorStatus(err))
+
+		return
+	}
+
+	SendOkResponse(writer, &models.DeleteResult{Deleted: 1})
+}
+
+func (h *Handler) PreviewPayload(writer http.ResponseWriter, request *http.Request) {
+	preview := &models.PayloadPreviewRequest{}
+
+	err := json.NewDecoder(request.Body).Decode(preview)
+	if err != nil {
+		http.Error(writer, models.ErrBadAttack.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	result, err := h.fuzzer.Preview(preview)
+	if err != nil {
+		http.Error(writer, err.Error(), attackErrorStatus(err))
+
+		return
+	}
+
+	SendOkResponse(writer, result)
+}
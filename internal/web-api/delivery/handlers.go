@@ -1,34 +1,62 @@
 package delivery
 
 import (
+	"errors"
 	"github.com/gorilla/mux"
 	"net/http"
-	"proxy/internal/network"
+	"proxy/internal/oob"
 	"proxy/internal/proxy/delivery"
+	"proxy/internal/web-api/models"
 	"proxy/internal/web-api/usecases"
 )
 
 type Handler struct {
-	storage usecases.WebApiInterface
-	proxy   *delivery.ProxyHandler
+	storage  usecases.WebApiInterface
+	projects *usecases.Projects
+	proxy    *delivery.ProxyHandler
+	pruner   *usecases.Pruner
+	fuzzer   *usecases.Fuzzer
+	scanner  *usecases.Scanner
+	siteMap  *usecases.SiteMap
+	listener *oob.Listener
 }
 
-func NewHandler(storage usecases.WebApiInterface, proxy *delivery.ProxyHandler) *Handler {
+func NewHandler(projects *usecases.Projects, proxy *delivery.ProxyHandler, pruner *usecases.Pruner,
+	fuzzer *usecases.Fuzzer, scanner *usecases.Scanner, siteMap *usecases.SiteMap, listener *oob.Listener) *Handler {
 	return &Handler{
-		storage: storage,
-		proxy:   proxy,
+		storage:  projects,
+		projects: projects,
+		proxy:    proxy,
+		pruner:   pruner,
+		fuzzer:   fuzzer,
+		scanner:  scanner,
+		siteMap:  siteMap,
+		listener: listener,
 	}
 }
 
 func (h *Handler) GetRequestsList(writer http.ResponseWriter, request *http.Request) {
-	reqs, err := h.storage.GetAllRequests()
+	filter, err := parseRequestsFilter(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	page, err := h.storage.ListRequests(filter)
+	if errors.Is(err, models.ErrBadCursor) {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
 	if err != nil {
 		http.Error(writer, err.Error(), http.StatusInternalServerError)
 
 		return
 	}
 
-	SendOkResponse(writer, reqs)
+	SendOkResponse(writer, page)
 }
 
 func (h *Handler) GetRequest(writer http.ResponseWriter, request *http.Request) {
@@ -44,25 +72,26 @@ func (h *Handler) GetRequest(writer http.ResponseWriter, request *http.Request)
 	SendOkResponse(writer, req)
 }
 
-func (h *Handler) RepeatRequest(writer http.ResponseWriter, request *http.Request) {
-	vars := mux.Vars(request)
+func (h *Handler) Search(writer http.ResponseWriter, request *http.Request) {
+	query, err := parseSearchQuery(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
 
-	req, err := h.storage.GetRequest(vars["id"])
-	if err != nil || req == nil {
-		http.Error(writer, "request not found", http.StatusInternalServerError)
+		return
+	}
+
+	hits, err := h.storage.Search(query)
+	if errors.Is(err, models.ErrBadSearchQuery) {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
 
 		return
 	}
 
-	resp, err := h.proxy.HandleHTTP(writer, req)
 	if err != nil {
-		http.Error(writer, "something went wrong while repeating request", http.StatusInternalServerError)
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
 
 		return
 	}
 
-	parsedResp := network.NewHTTPResponse(resp)
-	h.proxy.SendNewResponse(writer, parsedResp)
+	SendOkResponse(writer, hits)
 }
-
-func (h *Handler) ScanRequest(writer http.ResponseWriter, request *http.Request) {}
@@ -0,0 +1,62 @@
+package delivery
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"proxy/internal/har"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/usecases"
+)
+
+func (h *Handler) ImportTraffic(writer http.ResponseWriter, request *http.Request) {
+	data, err := io.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	query := request.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = models.ImportFormatRaw
+
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			format = models.ImportFormatHAR
+		}
+	}
+
+	var entries []*models.ImportEntry
+
+	switch format {
+	case models.ImportFormatHAR:
+		archive := &har.HAR{}
+
+		err = json.Unmarshal(data, archive)
+		if err != nil || archive.Log == nil {
+			http.Error(writer, models.ErrBadImport.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		entries = archive.ImportEntries()
+	case models.ImportFormatRaw:
+		scheme := query.Get("scheme")
+		if scheme == "" {
+			scheme = "http"
+		}
+
+		req, err := network.ParseRawRequest(data, scheme)
+		entries = []*models.ImportEntry{{Transaction: &models.Transaction{Request: req}, Err: err}}
+	default:
+		http.Error(writer, "unknown import format", http.StatusBadRequest)
+
+		return
+	}
+
+	SendOkResponse(writer, usecases.Import(h.projects.Storage(), entries))
+}
@@ -0,0 +1,62 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"io"
+	"net/http"
+	"proxy/internal/web-api/models"
+)
+
+func (h *Handler) GetInteractions(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	filter := &models.InteractionsFilter{
+		Token:    query.Get("token"),
+		Protocol: query.Get("protocol"),
+		Origin:   query.Get("origin"),
+		JobID:    query.Get("job_id"),
+	}
+
+	if filter.Protocol != "" && filter.Protocol != models.ProtocolHTTP && filter.Protocol != models.ProtocolDNS {
+		http.Error(writer, "invalid protocol", http.StatusBadRequest)
+
+		return
+	}
+
+	SendOkResponse(writer, h.listener.List(filter))
+}
+
+func (h *Handler) CreateInteractionToken(writer http.ResponseWriter, request *http.Request) {
+	body := &models.InteractionToken{}
+
+	err := json.NewDecoder(request.Body).Decode(body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		http.Error(writer, "invalid token request", http.StatusBadRequest)
+
+		return
+	}
+
+	token := h.listener.Token(&models.InteractionToken{
+		Origin:    models.OriginManual,
+		SourceID:  body.SourceID,
+		RequestID: body.RequestID,
+		Payload:   body.Payload,
+	})
+
+	SendOkResponse(writer, token)
+}
+
+func (h *Handler) GetInteractionToken(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	token, err := h.listener.GetToken(vars["token"])
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusNotFound)
+
+		return
+	}
+
+	SendOkResponse(writer, token)
+}
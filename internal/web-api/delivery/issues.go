@@ -0,0 +1,53 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"proxy/internal/web-api/models"
+)
+
+func (h *Handler) GetIssues(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	filter := &models.IssuesFilter{
+		Host:     query.Get("host"),
+		Path:     query.Get("path"),
+		Type:     query.Get("type"),
+		Severity: query.Get("severity"),
+	}
+
+	if filter.Severity != "" && !models.IsValidSeverity(filter.Severity) {
+		http.Error(writer, "invalid severity", http.StatusBadRequest)
+
+		return
+	}
+
+	issues, err := h.storage.ListIssues(filter)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, issues)
+}
+
+func (h *Handler) GetIssue(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	issue, err := h.storage.GetIssue(vars["id"])
+	if errors.Is(err, models.ErrIssueNotFound) {
+		http.Error(writer, err.Error(), http.StatusNotFound)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, issue)
+}
@@ -0,0 +1,28 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"proxy/internal/insertion"
+	"proxy/internal/web-api/models"
+)
+
+func (h *Handler) GetInsertionPoints(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	req, err := h.storage.GetRequest(vars["id"])
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(writer, "request not found", http.StatusNotFound)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, insertion.Points(req))
+}
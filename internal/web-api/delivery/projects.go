@@ -0,0 +1,120 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"proxy/internal/web-api/models"
+)
+
+func projectErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrBadProjectName), errors.Is(err, models.ErrBadArchive):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrProjectNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrProjectExists), errors.Is(err, models.ErrProjectProtected):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *Handler) GetProjects(writer http.ResponseWriter, request *http.Request) {
+	list, err := h.projects.List()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, list)
+}
+
+func (h *Handler) CreateProject(writer http.ResponseWriter, request *http.Request) {
+	project := &models.Project{}
+
+	err := json.NewDecoder(request.Body).Decode(project)
+	if err != nil {
+		http.Error(writer, "invalid project", http.StatusBadRequest)
+
+		return
+	}
+
+	err = h.projects.Create(project)
+	if err != nil {
+		http.Error(writer, err.Error(), projectErrorStatus(err))
+
+		return
+	}
+
+	SendOkResponse(writer, project)
+}
+
+func (h *Handler) SwitchProject(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	err := h.projects.Switch(vars["name"])
+	if err != nil {
+		http.Error(writer, err.Error(), projectErrorStatus(err))
+
+		return
+	}
+
+	h.GetProjects(writer, request)
+}
+
+func (h *Handler) DeleteProject(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	err := h.projects.Delete(vars["name"])
+	if err != nil {
+		http.Error(writer, err.Error(), projectErrorStatus(err))
+
+		return
+	}
+
+	h.GetProjects(writer, request)
+}
+
+func (h *Handler) ArchiveProject(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	archive, err := h.projects.Archive(vars["name"])
+	if err != nil {
+		http.Error(writer, err.Error(), projectErrorStatus(err))
+
+		return
+	}
+
+	archive.Scans = h.scanner.ProjectScans(archive.Project.Name)
+
+	writer.Header().Set("Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q", archive.Project.Name+".project.json"))
+
+	SendOkResponse(writer, archive)
+}
+
+func (h *Handler) RestoreProject(writer http.ResponseWriter, request *http.Request) {
+	archive := &models.ProjectArchive{}
+
+	err := json.NewDecoder(request.Body).Decode(archive)
+	if err != nil {
+		http.Error(writer, models.ErrBadArchive.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	project, err := h.projects.Restore(archive, request.URL.Query().Get("name"))
+	if err != nil {
+		http.Error(writer, err.Error(), projectErrorStatus(err))
+
+		return
+	}
+
+	h.scanner.Restore(project.Name, archive.Scans)
+
+	SendOkResponse(writer, project)
+}
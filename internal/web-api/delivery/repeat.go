@@ -0,0 +1,78 @@
+package delivery
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"io"
+	"net/http"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/usecases"
+)
+
+func (h *Handler) RepeatRequest(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	storage := h.projects.Storage()
+
+	req, err := storage.GetRequest(vars["id"])
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(writer, "request not found", http.StatusNotFound)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	data, err := io.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	req, err = modifyRequest(req, bytes.TrimSpace(data))
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	transaction, err := usecases.Repeat(storage, h.proxy, req, vars["id"])
+	if err != nil {
+		http.Error(writer, "something went wrong while repeating request", http.StatusBadGateway)
+
+		return
+	}
+
+	SendOkResponse(writer, transaction)
+}
+
+func modifyRequest(req *network.HTTPRequest, data []byte) (*network.HTTPRequest, error) {
+	switch {
+	case len(data) == 0:
+		return req, nil
+	case data[0] == '{':
+		patch := &models.RepeatPatch{}
+
+		err := json.Unmarshal(data, patch)
+		if err != nil {
+			return nil, models.ErrBadPatch
+		}
+
+		err = patch.Apply(req)
+		if err != nil {
+			return nil, err
+		}
+
+		return req, nil
+	default:
+		return network.ParseRawRequest(data, req.Scheme)
+	}
+}
@@ -0,0 +1,104 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/usecases"
+	"strconv"
+)
+
+func (h *Handler) DeleteRequest(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	err := h.storage.DeleteRequest(vars["id"])
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(writer, "request not found", http.StatusNotFound)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, &models.DeleteResult{Deleted: 1})
+}
+
+func (h *Handler) DeleteRequests(writer http.ResponseWriter, request *http.Request) {
+	all, _ := strconv.ParseBool(request.URL.Query().Get("all"))
+
+	filter, err := parseRequestsFilter(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	var deleted int
+
+	switch {
+	case all:
+		deleted, err = h.storage.DeleteAllRequests()
+	case filter.HasConditions():
+		deleted, err = usecases.DeleteRequests(h.storage, filter)
+	default:
+		http.Error(writer, "specify filters or all=true to delete the whole history", http.StatusBadRequest)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, &models.DeleteResult{Deleted: deleted})
+}
+
+func (h *Handler) GetRetentionPolicy(writer http.ResponseWriter, request *http.Request) {
+	policy, err := h.storage.GetRetentionPolicy()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, policy)
+}
+
+func (h *Handler) SaveRetentionPolicy(writer http.ResponseWriter, request *http.Request) {
+	policy := &models.RetentionPolicy{}
+
+	err := json.NewDecoder(request.Body).Decode(policy)
+	if err != nil {
+		http.Error(writer, "invalid retention policy", http.StatusBadRequest)
+
+		return
+	}
+
+	err = h.storage.SaveRetentionPolicy(policy)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, policy)
+}
+
+func (h *Handler) PruneHistory(writer http.ResponseWriter, request *http.Request) {
+	deleted, err := h.pruner.Prune()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, &models.DeleteResult{Deleted: deleted})
+}
@@ -0,0 +1,62 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/usecases"
+)
+
+func (h *Handler) GetRevisionTree(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	tree, err := usecases.RevisionTree(h.storage, vars["id"])
+	if errors.Is(err, models.ErrNotFound) {
+		http.Error(writer, "request not found", http.StatusNotFound)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, tree)
+}
+
+func (h *Handler) Diff(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	left, right := query.Get("left"), query.Get("right")
+	if left == "" || right == "" {
+		http.Error(writer, "left and right request ids are required", http.StatusBadRequest)
+
+		return
+	}
+
+	part := query.Get("part")
+	if part == "" {
+		part = models.DiffPartResponse
+	}
+
+	result, err := usecases.Diff(h.storage, left, right, part)
+	switch {
+	case errors.Is(err, models.ErrBadDiffPart):
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	case errors.Is(err, models.ErrNotFound):
+		http.Error(writer, "request not found", http.StatusNotFound)
+
+		return
+	case err != nil:
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, result)
+}
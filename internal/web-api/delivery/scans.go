@@ -0,0 +1,141 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"io"
+	"net/http"
+	"proxy/internal/web-api/models"
+)
+
+func scanErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrBadScan):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrOutOfScope):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrScanNotFound), errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *Handler) ScanRequest(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	var (
+		scan *models.Scan
+		err  error
+	)
+
+	switch scanType := request.URL.Query().Get("type"); scanType {
+	case "", models.ScanDiscovery:
+		config := &models.DiscoveryConfig{}
+
+		err = json.NewDecoder(request.Body).Decode(config)
+		if err != nil {
+			http.Error(writer, models.ErrBadScan.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		scan, err = h.scanner.StartDiscovery(vars["id"], config)
+	case models.ScanCORS:
+		config := &models.CORSConfig{}
+
+		err = json.NewDecoder(request.Body).Decode(config)
+		if err != nil && !errors.Is(err, io.EOF) {
+			http.Error(writer, models.ErrBadScan.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		scan, err = h.scanner.StartCORS(vars["id"], config)
+	case models.ScanRedirect:
+		config := &models.RedirectConfig{}
+
+		err = json.NewDecoder(request.Body).Decode(config)
+		if err != nil && !errors.Is(err, io.EOF) {
+			http.Error(writer, models.ErrBadScan.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		scan, err = h.scanner.StartRedirect(vars["id"], config)
+	case models.ScanXXE:
+		config := &models.XXEConfig{}
+
+		err = json.NewDecoder(request.Body).Decode(config)
+		if err != nil && !errors.Is(err, io.EOF) {
+			http.Error(writer, models.ErrBadScan.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		scan, err = h.scanner.StartXXE(vars["id"], config)
+	default:
+		http.Error(writer, "unknown scan type", http.StatusBadRequest)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), scanErrorStatus(err))
+
+		return
+	}
+
+	SendOkResponse(writer, scan)
+}
+
+func (h *Handler) GetScans(writer http.ResponseWriter, request *http.Request) {
+	SendOkResponse(writer, h.scanner.List())
+}
+
+func (h *Handler) GetScan(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	scan, err := h.scanner.Get(vars["id"])
+	if err != nil {
+		http.Error(writer, err.Error(), scanErrorStatus(err))
+
+		return
+	}
+
+	SendOkResponse(writer, scan)
+}
+
+func (h *Handler) StopScan(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	err := h.scanner.Stop(vars["id"])
+	if err != nil {
+		http.Error(writer, err.Error(), scanErrorStatus(err))
+
+		return
+	}
+
+	scan, err := h.scanner.Get(vars["id"])
+	if err != nil {
+		http.Error(writer, err.Error(), scanErrorStatus(err))
+
+		return
+	}
+
+	SendOkResponse(writer, scan)
+}
+
+func (h *Handler) DeleteScan(writer http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	err := h.scanner.Delete(vars["id"])
+	if err != nil {
+		http.Error(writer, err.Error(), scanErrorStatus(err))
+
+		return
+	}
+
+	SendOkResponse(writer, &models.DeleteResult{Deleted: 1})
+}
@@ -0,0 +1,45 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"proxy/internal/web-api/models"
+)
+
+func (h *Handler) GetScope(writer http.ResponseWriter, request *http.Request) {
+	scope, err := h.storage.GetScope()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, scope)
+}
+
+func (h *Handler) SaveScope(writer http.ResponseWriter, request *http.Request) {
+	scope := &models.Scope{}
+
+	err := json.NewDecoder(request.Body).Decode(scope)
+	if err != nil {
+		http.Error(writer, models.ErrBadScope.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	err = scope.Validate()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	err = h.storage.SaveScope(scope)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, scope)
+}
@@ -0,0 +1,16 @@
+package delivery
+
+import (
+	"net/http"
+)
+
+func (h *Handler) GetSiteMap(writer http.ResponseWriter, request *http.Request) {
+	nodes, err := h.siteMap.Get(request.URL.Query().Get("host"))
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	SendOkResponse(writer, nodes)
+}
@@ -0,0 +1,10 @@
+package models
+
+import "errors"
+
+const (
+	BodyPartRequest  = "request"
+	BodyPartResponse = "response"
+)
+
+var ErrBadBodyPart = errors.New("invalid body part")
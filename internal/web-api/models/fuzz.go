@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	AttackSniper       = "sniper"
+	AttackBatteringRam = "battering_ram"
+	AttackPitchfork    = "pitchfork"
+	AttackClusterBomb  = "cluster_bomb"
+
+	PayloadList     = "list"
+	PayloadNumbers  = "numbers"
+	PayloadCharset  = "charset"
+	PayloadCaptured = "captured"
+
+	PayloadFieldQuery  = "query"
+	PayloadFieldBody   = "body"
+	PayloadFieldHeader = "header"
+	PayloadFieldCookie = "cookie"
+
+	DefaultAttackConcurrency = 5
+	MaxAttackConcurrency     = 50
+)
+
+var (
+	ErrBadAttack      = errors.New("invalid attack configuration")
+	ErrAttackNotFound = errors.New("attack not found")
+)
+
+type PayloadSource struct {
+	Type      string   `json:"type"`
+	Values    []string `json:"values,omitempty"`
+	Path      string   `json:"path,omitempty"`
+	From      int64    `json:"from,omitempty"`
+	To        int64    `json:"to,omitempty"`
+	Step      int64    `json:"step,omitempty"`
+	Charset   string   `json:"charset,omitempty"`
+	MinLength int      `json:"min_length,omitempty"`
+	MaxLength int      `json:"max_length,omitempty"`
+	Field     string   `json:"field,omitempty"`
+	Name      string   `json:"name,omitempty"`
+}
+
+type AttackConfig struct {
+	RequestID   string              `json:"request_id"`
+	Template    string              `json:"template,omitempty"`
+	Points      []string            `json:"points,omitempty"`
+	Scheme      string              `json:"scheme,omitempty"`
+	Mode        string              `json:"mode"`
+	Payloads    []*PayloadSource    `json:"payloads"`
+	Processors  []*PayloadProcessor `json:"processors,omitempty"`
+	Concurrency int                 `json:"concurrency,omitempty"`
+	Delay       Duration            `json:"delay,omitempty"`
+	Grep        []string            `json:"grep,omitempty"`
+	Store       bool                `json:"store,omitempty"`
+}
+
+type AttackResult struct {
+	Index        int             `json:"index"`
+	Position     int             `json:"position"`
+	Payloads     []string        `json:"payloads"`
+	Status       int             `json:"status"`
+	Length       int             `json:"length"`
+	Time         time.Duration   `json:"time"`
+	Grep         map[string]bool `json:"grep,omitempty"`
+	RequestID    string          `json:"request_id,omitempty"`
+	Token        string          `json:"token,omitempty"`
+	Interactions int             `json:"interactions,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+type Attack struct {
+	ID         string          `json:"id"`
+	Project    string          `json:"project"`
+	Config     *AttackConfig   `json:"config"`
+	Status     string          `json:"status"`
+	Total      int             `json:"total"`
+	Done       int             `json:"done"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at,omitempty"`
+	Results    []*AttackResult `json:"results,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "errors"
+
+const (
+	ImportFormatHAR = "har"
+	ImportFormatRaw = "raw"
+)
+
+var ErrBadImport = errors.New("invalid import file")
+
+type ImportEntry struct {
+	Transaction *Transaction
+	Err         error
+}
+
+type ImportedEntry struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type ImportResult struct {
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	Entries  []*ImportedEntry `json:"entries"`
+}
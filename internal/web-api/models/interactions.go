@@ -0,0 +1,50 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ProtocolHTTP = "http"
+	ProtocolDNS  = "dns"
+
+	OriginScan   = "scan"
+	OriginFuzz   = "fuzz"
+	OriginManual = "manual"
+)
+
+var ErrTokenNotFound = errors.New("token not found")
+
+// InteractionToken describes where an out-of-band token was sent, so that interactions
+// can be traced back to the payload and the transaction that carried it.
+type InteractionToken struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	Domain    string    `json:"domain"`
+	Origin    string    `json:"origin"`
+	JobID     string    `json:"job_id,omitempty"`
+	SourceID  string    `json:"source_id,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Param     string    `json:"param,omitempty"`
+	Payload   string    `json:"payload,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Interaction is a request that reached the out-of-band listener with one of its tokens.
+type Interaction struct {
+	ID       string            `json:"id"`
+	Token    string            `json:"token"`
+	Protocol string            `json:"protocol"`
+	Source   string            `json:"source"`
+	Raw      string            `json:"raw"`
+	Time     time.Time         `json:"time"`
+	Origin   *InteractionToken `json:"origin,omitempty"`
+}
+
+type InteractionsFilter struct {
+	Token    string
+	Protocol string
+	Origin   string
+	JobID    string
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	SeverityInfo   = "info"
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+
+	MatchBody   = "body"
+	MatchHeader = "header"
+)
+
+var ErrIssueNotFound = errors.New("issue not found")
+
+// Issue is a finding about a host and path. Findings of the same type for the same
+// host, path and parameter are merged into one issue that counts its occurrences.
+type Issue struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Severity  string      `json:"severity"`
+	Title     string      `json:"title"`
+	Detail    string      `json:"detail,omitempty"`
+	Host      string      `json:"host"`
+	Path      string      `json:"path"`
+	Param     string      `json:"param,omitempty"`
+	Evidence  string      `json:"evidence,omitempty"`
+	Match     *IssueMatch `json:"match,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+	Count     int         `json:"count"`
+	FirstSeen time.Time   `json:"first_seen"`
+	LastSeen  time.Time   `json:"last_seen"`
+}
+
+// IssueMatch locates the evidence in the stored response: a byte offset into the body
+// or into one of the values of the named header.
+type IssueMatch struct {
+	Location string `json:"location"`
+	Header   string `json:"header,omitempty"`
+	Value    int    `json:"value,omitempty"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+}
+
+type IssuesFilter struct {
+	Host     string
+	Path     string
+	Type     string
+	Severity string
+}
+
+func (issue *Issue) Key() string {
+	return strings.Join([]string{issue.Type, strings.ToLower(issue.Host), issue.Path, issue.Param}, "\x00")
+}
+
+// Merge folds a newer occurrence of the same issue into this one.
+func (issue *Issue) Merge(newer *Issue) {
+	issue.Count += max(newer.Count, 1)
+
+	if SeverityRank(newer.Severity) > SeverityRank(issue.Severity) {
+		issue.Severity = newer.Severity
+	}
+
+	if newer.LastSeen.After(issue.LastSeen) {
+		issue.LastSeen = newer.LastSeen
+		issue.RequestID = newer.RequestID
+		issue.Evidence = newer.Evidence
+		issue.Match = newer.Match
+		issue.Detail = newer.Detail
+	}
+}
+
+func (filter *IssuesFilter) Match(issue *Issue) bool {
+	if filter.Host != "" && !strings.EqualFold(filter.Host, issue.Host) {
+		return false
+	}
+
+	if filter.Path != "" && filter.Path != issue.Path {
+		return false
+	}
+
+	if filter.Type != "" && filter.Type != issue.Type {
+		return false
+	}
+
+	return filter.Severity == "" || SeverityRank(issue.Severity) >= SeverityRank(filter.Severity)
+}
+
+func IsValidSeverity(severity string) bool {
+	return severity == SeverityInfo || SeverityRank(severity) > 0
+}
+
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityHigh:
+		return 3
+	case SeverityMedium:
+		return 2
+	case SeverityLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CompareIssues orders issues from the most severe, then by host and path.
+func CompareIssues(a, b *Issue) int {
+	if rank := SeverityRank(b.Severity) - SeverityRank(a.Severity); rank != 0 {
+		return rank
+	}
+
+	if host := strings.Compare(a.Host, b.Host); host != 0 {
+		return host
+	}
+
+	if path := strings.Compare(a.Path, b.Path); path != 0 {
+		return path
+	}
+
+	return strings.Compare(a.Type, b.Type)
+}
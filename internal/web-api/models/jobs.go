@@ -0,0 +1,7 @@
+package models
+
+const (
+	JobRunning  = "running"
+	JobFinished = "finished"
+	JobStopped  = "stopped"
+)
@@ -0,0 +1,41 @@
+package models
+
+const (
+	ProcessorURLEncode       = "url_encode"
+	ProcessorDoubleURLEncode = "double_url_encode"
+	ProcessorBase64          = "base64"
+	ProcessorHex             = "hex"
+	ProcessorHTMLEntities    = "html_entities"
+	ProcessorHash            = "hash"
+	ProcessorPrefix          = "prefix"
+	ProcessorSuffix          = "suffix"
+	ProcessorUppercase       = "uppercase"
+	ProcessorLowercase       = "lowercase"
+	ProcessorJSONEscape      = "json_escape"
+
+	HashMD5    = "md5"
+	HashSHA1   = "sha1"
+	HashSHA256 = "sha256"
+)
+
+type PayloadProcessor struct {
+	Type      string `json:"type"`
+	Value     string `json:"value,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+type PayloadPreviewRequest struct {
+	Payload    string              `json:"payload"`
+	Processors []*PayloadProcessor `json:"processors"`
+	RequestID  string              `json:"request_id,omitempty"`
+	Template   string              `json:"template,omitempty"`
+	Position   int                 `json:"position,omitempty"`
+	Point      string              `json:"point,omitempty"`
+}
+
+type PayloadPreview struct {
+	Payload   string   `json:"payload"`
+	Processed string   `json:"processed"`
+	Steps     []string `json:"steps"`
+	Context   string   `json:"context,omitempty"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+const (
+	DefaultProject = "default"
+
+	ProjectArchiveVersion = 1
+)
+
+var (
+	ErrBadProjectName   = errors.New("invalid project name")
+	ErrProjectExists    = errors.New("project already exists")
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrProjectProtected = errors.New("project can not be deleted")
+	ErrBadArchive       = errors.New("invalid project archive")
+
+	projectNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+)
+
+type Project struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ProjectsList struct {
+	Current  string     `json:"current"`
+	Projects []*Project `json:"projects"`
+}
+
+type ProjectArchive struct {
+	Version      int              `json:"version"`
+	Project      *Project         `json:"project"`
+	Retention    *RetentionPolicy `json:"retention,omitempty"`
+	Scope        *Scope           `json:"scope,omitempty"`
+	Transactions []*Transaction   `json:"transactions"`
+	Issues       []*Issue         `json:"issues,omitempty"`
+	Scans        []*Scan          `json:"scans,omitempty"`
+}
+
+func IsValidProjectName(name string) bool {
+	return projectNameRegexp.MatchString(name)
+}
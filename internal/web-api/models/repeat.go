@@ -0,0 +1,224 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"proxy/internal/insertion"
+	"proxy/internal/network"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrBadPatch = errors.New("invalid repeat patch")
+
+type ValuesPatch struct {
+	Add     map[string][]string `json:"add,omitempty"`
+	Replace map[string][]string `json:"replace,omitempty"`
+	Remove  []string            `json:"remove,omitempty"`
+}
+
+type RepeatPatch struct {
+	Method       string                   `json:"method,omitempty"`
+	Scheme       string                   `json:"scheme,omitempty"`
+	Host         string                   `json:"host,omitempty"`
+	Port         string                   `json:"port,omitempty"`
+	Path         string                   `json:"path,omitempty"`
+	Headers      *ValuesPatch             `json:"headers,omitempty"`
+	Query        *ValuesPatch             `json:"query,omitempty"`
+	Cookies      *ValuesPatch             `json:"cookies,omitempty"`
+	Body         *string                  `json:"body,omitempty"`
+	BodyEncoding string                   `json:"body_encoding,omitempty"`
+	Parts        []*network.MultipartPart `json:"parts,omitempty"`
+	Points       map[string]string        `json:"points,omitempty"`
+}
+
+func (patch *ValuesPatch) apply(values map[string][]string, canonical func(string) string) {
+	if patch == nil {
+		return
+	}
+
+	for _, name := range patch.Remove {
+		delete(values, canonical(name))
+	}
+
+	for name, replacement := range patch.Replace {
+		values[canonical(name)] = replacement
+	}
+
+	for name, added := range patch.Add {
+		values[canonical(name)] = append(values[canonical(name)], added...)
+	}
+}
+
+func (patch *RepeatPatch) Apply(request *network.HTTPRequest) error {
+	if patch.Method != "" {
+		request.Method = strings.ToUpper(patch.Method)
+	}
+
+	if patch.Scheme != "" {
+		request.Scheme = strings.ToLower(patch.Scheme)
+	}
+
+	if patch.Host != "" {
+		request.Host = patch.Host
+	}
+
+	if patch.Port != "" {
+		if _, err := strconv.Atoi(patch.Port); err != nil {
+			return ErrBadPatch
+		}
+
+		request.Port = patch.Port
+	}
+
+	if patch.Path != "" {
+		if !strings.HasPrefix(patch.Path, "/") {
+			return ErrBadPatch
+		}
+
+		request.Path = patch.Path
+	}
+
+	if request.Header == nil {
+		request.Header = http.Header{}
+	}
+
+	patch.Headers.apply(request.Header, http.CanonicalHeaderKey)
+
+	if request.GetParams == nil {
+		request.GetParams = url.Values{}
+	}
+
+	patch.Query.apply(request.GetParams, keep)
+
+	if patch.Cookies != nil {
+		request.Cookies = patchCookies(request.Cookies, patch.Cookies)
+		request.Header.Del("Cookie")
+	}
+
+	if patch.Body != nil {
+		err := setBody(request, *patch.Body, patch.BodyEncoding)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Header and body patches may both change how the body splits into parts.
+	request.ParseParts()
+
+	if patch.Parts != nil {
+		err := request.SetParts(patch.Parts)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrBadPatch, err)
+		}
+	}
+
+	return patch.applyPoints(request)
+}
+
+// applyPoints sets insertion points such as "json:/user/name" after the body patch, in name
+// order, so that several points of one body all end up in it.
+func (patch *RepeatPatch) applyPoints(request *network.HTTPRequest) error {
+	names := make([]string, 0, len(patch.Points))
+	for name := range patch.Points {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		point, err := insertion.ParsePoint(name)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrBadPatch, name, err)
+		}
+
+		injected, err := insertion.Inject(request, point, patch.Points[name])
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrBadPatch, name, err)
+		}
+
+		*request = *injected
+	}
+
+	return nil
+}
+
+func keep(name string) string {
+	return name
+}
+
+func patchCookies(cookies []*http.Cookie, patch *ValuesPatch) []*http.Cookie {
+	values := make(map[string][]string)
+	order := make([]string, 0, len(cookies))
+
+	for _, cookie := range cookies {
+		if _, ok := values[cookie.Name]; !ok {
+			order = append(order, cookie.Name)
+		}
+
+		values[cookie.Name] = append(values[cookie.Name], cookie.Value)
+	}
+
+	patch.apply(values, keep)
+
+	for name := range patch.Replace {
+		order = append(order, name)
+	}
+
+	for name := range patch.Add {
+		order = append(order, name)
+	}
+
+	patched := make([]*http.Cookie, 0, len(values))
+	seen := make(map[string]bool)
+
+	for _, name := range order {
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+
+		for _, value := range values[name] {
+			patched = append(patched, &http.Cookie{Name: name, Value: value})
+		}
+	}
+
+	return patched
+}
+
+func setBody(request *network.HTTPRequest, body, encoding string) error {
+	data := []byte(body)
+
+	if encoding == "base64" {
+		var err error
+
+		data, err = base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return ErrBadPatch
+		}
+	}
+
+	request.Body = data
+	request.PostParams = url.Values{}
+
+	if request.Header.Get("Content-Length") != "" {
+		request.Header.Set("Content-Length", strconv.Itoa(len(data)))
+	}
+
+	if !strings.HasPrefix(request.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return nil
+	}
+
+	params, err := url.ParseQuery(body)
+	if err == nil {
+		request.PostParams = params
+		request.Body = []byte{}
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"proxy/internal/network"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SortByID       = "id"
+	SortByTime     = "time"
+	SortByDuration = "duration"
+	SortBySize     = "size"
+
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+var (
+	ErrBadCursor = errors.New("invalid cursor")
+	ErrNotFound  = errors.New("not found")
+)
+
+type RequestSummary struct {
+	ID          string        `json:"id"`
+	ParentID    string        `json:"parent_id,omitempty"`
+	Method      string        `json:"method"`
+	Scheme      string        `json:"scheme"`
+	Host        string        `json:"host"`
+	Port        string        `json:"port"`
+	Path        string        `json:"path"`
+	Time        time.Time     `json:"time"`
+	RequestSize int           `json:"request_size"`
+	Code        int           `json:"code,omitempty"`
+	ContentType string        `json:"content_type,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	Size        int           `json:"size"`
+}
+
+type Cursor struct {
+	Score float64
+	ID    string
+}
+
+type RequestsFilter struct {
+	Method      string
+	Host        string
+	PathGlob    string
+	StatusCode  int
+	ContentType string
+	ParentID    string
+	From        time.Time
+	To          time.Time
+	SortBy      string
+	Desc        bool
+	Cursor      string
+	Limit       int
+}
+
+type Transaction struct {
+	Request  *network.HTTPRequest  `json:"request"`
+	Response *network.HTTPResponse `json:"response,omitempty"`
+}
+
+type RequestsPage struct {
+	Requests   []*RequestSummary `json:"requests"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+func IsValidSort(sortBy string) bool {
+	switch sortBy {
+	case SortByID, SortByTime, SortByDuration, SortBySize:
+		return true
+	}
+
+	return false
+}
+
+func (filter *RequestsFilter) HasConditions() bool {
+	return filter.Method != "" || filter.Host != "" || filter.PathGlob != "" || filter.StatusCode != 0 ||
+		filter.ContentType != "" || filter.ParentID != "" || !filter.From.IsZero() || !filter.To.IsZero()
+}
+
+func (filter *RequestsFilter) MatchPath(requestPath string) bool {
+	if filter.PathGlob == "" {
+		return true
+	}
+
+	matched, err := path.Match(filter.PathGlob, requestPath)
+	if err != nil {
+		return false
+	}
+
+	return matched
+}
+
+func (filter *RequestsFilter) Match(summary *RequestSummary) bool {
+	if filter.Method != "" && !strings.EqualFold(filter.Method, summary.Method) {
+		return false
+	}
+
+	if filter.Host != "" && !strings.EqualFold(filter.Host, summary.Host) {
+		return false
+	}
+
+	if filter.StatusCode != 0 && filter.StatusCode != summary.Code {
+		return false
+	}
+
+	if filter.ContentType != "" && !strings.EqualFold(filter.ContentType, summary.ContentType) {
+		return false
+	}
+
+	if filter.ParentID != "" && filter.ParentID != summary.ParentID {
+		return false
+	}
+
+	if !filter.From.IsZero() && summary.Time.UnixMilli() < filter.From.UnixMilli() {
+		return false
+	}
+
+	if !filter.To.IsZero() && summary.Time.UnixMilli() > filter.To.UnixMilli() {
+		return false
+	}
+
+	return filter.MatchPath(summary.Path)
+}
+
+func NewRequestSummary(request *network.HTTPRequest) *RequestSummary {
+	return &RequestSummary{
+		ID:          request.ID,
+		ParentID:    request.ParentID,
+		Method:      request.Method,
+		Scheme:      request.Scheme,
+		Host:        request.Host,
+		Port:        request.Port,
+		Path:        request.Path,
+		Time:        request.Time,
+		RequestSize: len(request.Body),
+	}
+}
+
+func (summary *RequestSummary) SetResponse(response *network.HTTPResponse) {
+	summary.Code = response.Code
+	summary.ContentType = response.ContentType()
+	summary.Duration = response.Duration
+	summary.Size = len(response.Body)
+}
+
+func (summary *RequestSummary) SortScore(sortBy string) float64 {
+	switch sortBy {
+	case SortByTime:
+		return float64(summary.Time.UnixMilli())
+	case SortByDuration:
+		return float64(summary.Duration.Milliseconds())
+	case SortBySize:
+		return float64(summary.Size)
+	default:
+		id, _ := strconv.Atoi(summary.ID)
+
+		return float64(id)
+	}
+}
+
+func EncodeCursor(score float64, id string) string {
+	raw := fmt.Sprintf("%s:%s", strconv.FormatFloat(score, 'f', -1, 64), id)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(raw string) (*Cursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+
+	score, id, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return nil, ErrBadCursor
+	}
+
+	parsedScore, err := strconv.ParseFloat(score, 64)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+
+	return &Cursor{Score: parsedScore, ID: id}, nil
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+type Duration time.Duration
+
+func (duration Duration) MarshalJSON() ([]byte, error) {
+	if duration == 0 {
+		return json.Marshal("")
+	}
+
+	return json.Marshal(time.Duration(duration).String())
+}
+
+func (duration *Duration) UnmarshalJSON(data []byte) error {
+	var raw string
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	if raw == "" {
+		*duration = 0
+
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+
+	*duration = Duration(parsed)
+
+	return nil
+}
+
+type RetentionRule struct {
+	MaxEntries   int      `json:"max_entries,omitempty"`
+	MaxAge       Duration `json:"max_age,omitempty"`
+	MaxBodyBytes int64    `json:"max_body_bytes,omitempty"`
+}
+
+type RetentionPolicy struct {
+	RetentionRule
+	Hosts map[string]RetentionRule `json:"hosts,omitempty"`
+}
+
+type DeleteResult struct {
+	Deleted int `json:"deleted"`
+}
+
+func (rule RetentionRule) IsEmpty() bool {
+	return rule.MaxEntries <= 0 && rule.MaxAge <= 0 && rule.MaxBodyBytes <= 0
+}
+
+func (policy *RetentionPolicy) RuleFor(host string) (string, RetentionRule) {
+	for ruleHost, rule := range policy.Hosts {
+		if strings.EqualFold(ruleHost, host) {
+			return strings.ToLower(ruleHost), rule
+		}
+	}
+
+	return "", policy.RetentionRule
+}
+
+func (policy *RetentionPolicy) IsEmpty() bool {
+	if !policy.RetentionRule.IsEmpty() {
+		return false
+	}
+
+	for _, rule := range policy.Hosts {
+		if !rule.IsEmpty() {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"errors"
+	"proxy/internal/diff"
+)
+
+const (
+	DiffPartRequest  = "request"
+	DiffPartResponse = "response"
+)
+
+var ErrBadDiffPart = errors.New("invalid diff part")
+
+type RevisionNode struct {
+	*RequestSummary
+	Children []*RevisionNode `json:"children"`
+}
+
+type DiffResult struct {
+	Left    string         `json:"left"`
+	Right   string         `json:"right"`
+	Part    string         `json:"part"`
+	Headers []*diff.Line   `json:"headers"`
+	Body    []*diff.Line   `json:"body"`
+	JSON    []*diff.Change `json:"json,omitempty"`
+}
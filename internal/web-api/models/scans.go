@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ScanDiscovery = "discovery"
+	ScanCORS      = "cors"
+	ScanRedirect  = "redirect"
+	ScanXXE       = "xxe"
+
+	ClassFound     = "found"
+	ClassRedirect  = "redirect"
+	ClassForbidden = "forbidden"
+	ClassError     = "error"
+	ClassOther     = "other"
+
+	DefaultScanConcurrency = 10
+	MaxScanConcurrency     = 50
+
+	DefaultCallbackWait = Duration(3 * time.Second)
+)
+
+var (
+	ErrBadScan      = errors.New("invalid scan configuration")
+	ErrScanNotFound = errors.New("scan not found")
+)
+
+type DiscoveryConfig struct {
+	Words       []string            `json:"words,omitempty"`
+	Wordlist    string              `json:"wordlist,omitempty"`
+	Extensions  []string            `json:"extensions,omitempty"`
+	BasePath    string              `json:"base_path,omitempty"`
+	Concurrency int                 `json:"concurrency,omitempty"`
+	Delay       Duration            `json:"delay,omitempty"`
+	Processors  []*PayloadProcessor `json:"processors,omitempty"`
+}
+
+type DiscoveryResult struct {
+	Path           string `json:"path"`
+	URL            string `json:"url"`
+	Status         int    `json:"status"`
+	Length         int    `json:"length"`
+	RedirectTo     string `json:"redirect_to,omitempty"`
+	Classification string `json:"classification"`
+	RequestID      string `json:"request_id,omitempty"`
+}
+
+type CORSConfig struct {
+	Origins    []string            `json:"origins,omitempty"`
+	Processors []*PayloadProcessor `json:"processors,omitempty"`
+}
+
+type RedirectConfig struct {
+	Params     []string            `json:"params,omitempty"`
+	Wait       Duration            `json:"wait,omitempty"`
+	Processors []*PayloadProcessor `json:"processors,omitempty"`
+}
+
+type XXEConfig struct {
+	Params     []string            `json:"params,omitempty"`
+	Wait       Duration            `json:"wait,omitempty"`
+	Processors []*PayloadProcessor `json:"processors,omitempty"`
+}
+
+// ProbeResult is one request sent by an active check.
+type ProbeResult struct {
+	Name      string `json:"name"`
+	Param     string `json:"param,omitempty"`
+	Payload   string `json:"payload"`
+	RequestID string `json:"request_id,omitempty"`
+	Status    int    `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Severity  string `json:"severity,omitempty"`
+	Finding   string `json:"finding,omitempty"`
+	IssueID   string `json:"issue_id,omitempty"`
+}
+
+type Scan struct {
+	ID         string             `json:"id"`
+	Project    string             `json:"project"`
+	Type       string             `json:"type"`
+	RequestID  string             `json:"request_id"`
+	Status     string             `json:"status"`
+	Total      int                `json:"total"`
+	Done       int                `json:"done"`
+	Errors     int                `json:"errors"`
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt time.Time          `json:"finished_at,omitempty"`
+	Discovery  *DiscoveryConfig   `json:"discovery,omitempty"`
+	CORS       *CORSConfig        `json:"cors,omitempty"`
+	Redirect   *RedirectConfig    `json:"redirect,omitempty"`
+	XXE        *XXEConfig         `json:"xxe,omitempty"`
+	Found      []*DiscoveryResult `json:"found,omitempty"`
+	Probes     []*ProbeResult     `json:"probes,omitempty"`
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"proxy/internal/network"
+	"regexp"
+	"strings"
+)
+
+const (
+	OutOfScopeForward = "forward"
+	OutOfScopeBlock   = "block"
+)
+
+var (
+	ErrBadScope   = errors.New("invalid scope")
+	ErrOutOfScope = errors.New("target is out of scope")
+)
+
+// ScopeRule matches requests by scheme, host, port and path. Empty fields match anything,
+// a host starting with "*." matches all of its subdomains and the path is a regular expression.
+type ScopeRule struct {
+	Scheme string `json:"scheme,omitempty"`
+	Host   string `json:"host,omitempty"`
+	Port   string `json:"port,omitempty"`
+	Path   string `json:"path,omitempty"`
+
+	path *regexp.Regexp
+}
+
+// Scope puts a request in scope when it matches an include rule, or there are none,
+// and matches no exclude rule. OutOfScope tells the proxy to forward or block the rest.
+type Scope struct {
+	Include    []*ScopeRule `json:"include,omitempty"`
+	Exclude    []*ScopeRule `json:"exclude,omitempty"`
+	OutOfScope string       `json:"out_of_scope,omitempty"`
+}
+
+func (scope *Scope) Validate() error {
+	if scope.OutOfScope != "" && scope.OutOfScope != OutOfScopeForward && scope.OutOfScope != OutOfScopeBlock {
+		return fmt.Errorf("%w: unknown out of scope action %q", ErrBadScope, scope.OutOfScope)
+	}
+
+	for _, rules := range [][]*ScopeRule{scope.Include, scope.Exclude} {
+		for _, rule := range rules {
+			err := rule.validate()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (rule *ScopeRule) validate() error {
+	if rule == nil {
+		return fmt.Errorf("%w: empty rule", ErrBadScope)
+	}
+
+	scheme := strings.ToLower(rule.Scheme)
+	if scheme != "" && scheme != "http" && scheme != "https" {
+		return fmt.Errorf("%w: unknown scheme %q", ErrBadScope, rule.Scheme)
+	}
+
+	if rule.Path == "" {
+		return nil
+	}
+
+	var err error
+
+	rule.path, err = regexp.Compile(rule.Path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBadScope, err)
+	}
+
+	return nil
+}
+
+func (scope *Scope) IsEmpty() bool {
+	return len(scope.Include) == 0 && len(scope.Exclude) == 0
+}
+
+func (scope *Scope) Blocks() bool {
+	return scope.OutOfScope == OutOfScopeBlock
+}
+
+func (scope *Scope) Allows(request *network.HTTPRequest) bool {
+	return scope.allows(request.Scheme, request.Host, request.Port, request.Path, true)
+}
+
+// AllowsHost reports whether some request to the host can be in scope. Path rules are
+// ignored for includes, and only exclude rules without a path rule out the whole host.
+func (scope *Scope) AllowsHost(scheme, host, port string) bool {
+	return scope.allows(scheme, host, port, "", false)
+}
+
+func (scope *Scope) allows(scheme, host, port, path string, withPath bool) bool {
+	included := len(scope.Include) == 0
+
+	for _, rule := range scope.Include {
+		if rule.matches(scheme, host, port) && (!withPath || rule.matchesPath(path)) {
+			included = true
+
+			break
+		}
+	}
+
+	if !included {
+		return false
+	}
+
+	for _, rule := range scope.Exclude {
+		if !withPath && rule.Path != "" {
+			continue
+		}
+
+		if rule.matches(scheme, host, port) && (!withPath || rule.matchesPath(path)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (rule *ScopeRule) matches(scheme, host, port string) bool {
+	scheme = strings.ToLower(scheme)
+
+	if rule.Scheme != "" && !strings.EqualFold(rule.Scheme, scheme) {
+		return false
+	}
+
+	if rule.Port != "" {
+		if port == "" {
+			port = defaultPort(scheme)
+		}
+
+		if rule.Port != port {
+			return false
+		}
+	}
+
+	if rule.Host == "" {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	ruleHost := strings.ToLower(rule.Host)
+
+	if suffix, ok := strings.CutPrefix(ruleHost, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+
+	return host == ruleHost
+}
+
+func (rule *ScopeRule) matchesPath(path string) bool {
+	if rule.Path == "" {
+		return true
+	}
+
+	if rule.path == nil {
+		compiled, err := regexp.Compile(rule.Path)
+		if err != nil {
+			return false
+		}
+
+		rule.path = compiled
+	}
+
+	return rule.path.MatchString(path)
+}
+
+func defaultPort(scheme string) string {
+	if scheme == "https" {
+		return "443"
+	}
+
+	return "80"
+}
@@ -0,0 +1,48 @@
+package models
+
+import "errors"
+
+const (
+	SearchModeLiteral = "literal"
+	SearchModeRegex   = "regex"
+
+	FieldRequestHeaders  = "request_headers"
+	FieldRequestBody     = "request_body"
+	FieldResponseHeaders = "response_headers"
+	FieldResponseBody    = "response_body"
+
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 500
+)
+
+var ErrBadSearchQuery = errors.New("invalid search query")
+
+type SearchQuery struct {
+	Query      string
+	Mode       string
+	IgnoreCase bool
+	Fields     []string
+	Limit      int
+}
+
+type SearchMatch struct {
+	Field          string `json:"field"`
+	Offset         int    `json:"offset"`
+	Snippet        string `json:"snippet"`
+	HighlightStart int    `json:"highlight_start"`
+	HighlightEnd   int    `json:"highlight_end"`
+}
+
+type SearchHit struct {
+	ID      string         `json:"id"`
+	Matches []*SearchMatch `json:"matches"`
+}
+
+func IsValidSearchField(field string) bool {
+	switch field {
+	case FieldRequestHeaders, FieldRequestBody, FieldResponseHeaders, FieldResponseBody:
+		return true
+	}
+
+	return false
+}
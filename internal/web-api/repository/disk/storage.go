@@ -0,0 +1,216 @@
+package disk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/repository/local"
+	"strings"
+)
+
+const (
+	requestsDir  = "requests"
+	responsesDir = "responses"
+	valuesDir    = "values"
+	projectsDir  = "projects"
+)
+
+var ErrBadID = errors.New("invalid storage id")
+
+type blobs struct {
+	dir string
+}
+
+func NewStorage(dir string) (*local.Storage, error) {
+	for _, sub := range []string{requestsDir, responsesDir, valuesDir} {
+		err := os.MkdirAll(filepath.Join(dir, sub), 0o755)
+		if err != nil {
+			log.Println("error creating storage directory", err)
+
+			return nil, err
+		}
+	}
+
+	return local.NewStorage(&blobs{dir: dir}, 0)
+}
+
+func (blobs *blobs) path(kind, id string) (string, error) {
+	if id == "" || id == "." || strings.Contains(id, "..") || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("%w: %q", ErrBadID, id)
+	}
+
+	return filepath.Join(blobs.dir, kind, id+".json"), nil
+}
+
+func (blobs *blobs) write(kind, id string, value any) error {
+	path, err := blobs.path(kind, id)
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		log.Println("error serializing", kind, err)
+
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(blobs.dir, kind), ".tmp-*")
+	if err != nil {
+		log.Println("error creating temporary file", err)
+
+		return err
+	}
+
+	_, err = tmp.Write(jsonData)
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		log.Println("error writing", kind, err)
+
+		return err
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		os.Remove(tmp.Name())
+		log.Println("error writing", kind, err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (blobs *blobs) read(kind, id string, value any) error {
+	path, err := blobs.path(kind, id)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return models.ErrNotFound
+	}
+
+	if err != nil {
+		log.Println("error reading", kind, err)
+
+		return err
+	}
+
+	err = json.Unmarshal(data, value)
+	if err != nil {
+		log.Println("error deserializing", kind, err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (blobs *blobs) SaveRequest(request *network.HTTPRequest) error {
+	return blobs.write(requestsDir, request.ID, request)
+}
+
+func (blobs *blobs) SaveResponse(response *network.HTTPResponse) error {
+	return blobs.write(responsesDir, response.ID, response)
+}
+
+func (blobs *blobs) GetRequest(id string) (*network.HTTPRequest, error) {
+	var parsedReq network.HTTPRequest
+
+	err := blobs.read(requestsDir, id, &parsedReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsedReq, nil
+}
+
+func (blobs *blobs) GetResponse(id string) (*network.HTTPResponse, error) {
+	var parsedResp network.HTTPResponse
+
+	err := blobs.read(responsesDir, id, &parsedResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsedResp, nil
+}
+
+func (blobs *blobs) Delete(id string) error {
+	for _, kind := range []string{requestsDir, responsesDir} {
+		path, err := blobs.path(kind, id)
+		if err != nil {
+			return err
+		}
+
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Println("error deleting", kind, err)
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (blobs *blobs) IDs() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(blobs.dir, requestsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		id, found := strings.CutSuffix(entry.Name(), ".json")
+		if !found || entry.IsDir() {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (blobs *blobs) SaveValue(key string, value any) error {
+	return blobs.write(valuesDir, key, value)
+}
+
+func (blobs *blobs) GetValue(key string, value any) error {
+	return blobs.read(valuesDir, key, value)
+}
+
+func NewProjects(dir string) (*local.Projects, error) {
+	return local.NewProjects(func(name string) (*local.Storage, error) {
+		return NewStorage(projectDir(dir, name))
+	}, func(name string) error {
+		return os.RemoveAll(projectDir(dir, name))
+	})
+}
+
+func projectDir(dir, name string) string {
+	if name == models.DefaultProject {
+		return dir
+	}
+
+	return filepath.Join(dir, projectsDir, name)
+}
@@ -0,0 +1,46 @@
+package disk
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"proxy/internal/network"
+	"proxy/internal/web-api/repository/storagetest"
+	"proxy/internal/web-api/usecases"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) usecases.WebApiInterface {
+		storage, err := NewStorage(t.TempDir())
+		if err != nil {
+			t.Fatalf("NewStorage: %v", err)
+		}
+
+		return storage
+	})
+}
+
+func TestStorageRejectsPathIDs(t *testing.T) {
+	dir := t.TempDir()
+
+	storage, err := NewStorage(filepath.Join(dir, "storage"))
+	if err != nil {
+		t.Fatalf("NewStorage: %v", err)
+	}
+
+	for _, id := range []string{"../../escaped", "a/b", `a\b`, ".."} {
+		request := &network.HTTPRequest{ID: id, Method: "GET", Host: "example.com", Header: http.Header{}}
+
+		err := storage.RestoreTransaction(request, nil)
+		if !errors.Is(err, ErrBadID) {
+			t.Errorf("RestoreTransaction(%q) error = %v, want %v", id, err, ErrBadID)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "escaped.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("file written outside the storage directory: %v", err)
+	}
+}
@@ -0,0 +1,535 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/search"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	idIndex       = "index:id"
+	timeIndex     = "index:time"
+	durationIndex = "index:duration"
+	sizeIndex     = "index:size"
+	pathIndex     = "index:path"
+
+	// indexedKey marks a storage whose requests, including ones saved before the indices
+	// existed, have all been indexed.
+	indexedKey = "indexed"
+
+	tmpKeyTTL = time.Minute
+)
+
+func (storage *Storage) methodIndex(method string) string {
+	return storage.key("index:method:%s", strings.ToUpper(method))
+}
+
+func (storage *Storage) hostIndex(host string) string {
+	return storage.key("index:host:%s", strings.ToLower(host))
+}
+
+func (storage *Storage) statusIndex(code int) string {
+	return storage.key("index:status:%d", code)
+}
+
+func (storage *Storage) contentTypeIndex(contentType string) string {
+	return storage.key("index:content_type:%s", strings.ToLower(contentType))
+}
+
+func (storage *Storage) parentIndex(parentID string) string {
+	return storage.key("index:parent:%s", parentID)
+}
+
+func (storage *Storage) sortIndex(sortBy string) string {
+	switch sortBy {
+	case models.SortByTime:
+		return storage.key(timeIndex)
+	case models.SortByDuration:
+		return storage.key(durationIndex)
+	case models.SortBySize:
+		return storage.key(sizeIndex)
+	default:
+		return storage.key(idIndex)
+	}
+}
+
+// backfill indexes the requests and responses stored before the indices were kept.
+func (storage *Storage) backfill() error {
+	ctx := context.Background()
+
+	indexed, err := storage.client.Exists(ctx, storage.key(indexedKey)).Result()
+	if err != nil || indexed > 0 {
+		return err
+	}
+
+	var cursor uint64
+
+	for {
+		keys, next, err := storage.client.Scan(ctx, cursor, storage.key("request_*"), scanBatchSize).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			id := strings.TrimPrefix(key, storage.key("request_"))
+			if _, err := strconv.Atoi(id); err != nil {
+				continue
+			}
+
+			err = storage.backfillTransaction(id)
+			if err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			break
+		}
+
+		cursor = next
+	}
+
+	return storage.client.Set(ctx, storage.key(indexedKey), 1, 0).Err()
+}
+
+func (storage *Storage) backfillTransaction(id string) error {
+	request, err := storage.GetRequest(id)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	request.ID = id
+
+	err = storage.indexRequest(request)
+	if err == nil {
+		err = storage.indexSearch(id, search.RequestFields(request))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	response, err := storage.GetResponse(id)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = storage.indexResponse(response, id)
+	if err != nil {
+		return err
+	}
+
+	return storage.indexSearch(id, search.ResponseFields(response))
+}
+
+func (storage *Storage) indexRequest(request *network.HTTPRequest) error {
+	id, _ := strconv.Atoi(request.ID)
+
+	summary := models.NewRequestSummary(request)
+
+	jsonData, err := json.Marshal(summary)
+	if err != nil {
+		log.Println("error serializing request summary", err)
+
+		return err
+	}
+
+	ctx := context.Background()
+
+	_, err = storage.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, storage.key("summary_%s", request.ID), jsonData, 0)
+		pipe.ZAdd(ctx, storage.key(idIndex), redis.Z{Score: float64(id), Member: request.ID})
+		pipe.ZAdd(ctx, storage.key(timeIndex), redis.Z{Score: float64(request.Time.UnixMilli()), Member: request.ID})
+		pipe.ZAdd(ctx, storage.key(durationIndex), redis.Z{Score: 0, Member: request.ID})
+		pipe.ZAdd(ctx, storage.key(sizeIndex), redis.Z{Score: 0, Member: request.ID})
+		pipe.SAdd(ctx, storage.methodIndex(request.Method), request.ID)
+		pipe.SAdd(ctx, storage.hostIndex(request.Host), request.ID)
+		pipe.HSet(ctx, storage.key(pathIndex), request.ID, request.Path)
+
+		if request.ParentID != "" {
+			pipe.SAdd(ctx, storage.parentIndex(request.ParentID), request.ID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Println("error indexing request", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (storage *Storage) indexResponse(response *network.HTTPResponse, id string) error {
+	ctx := context.Background()
+
+	summary, err := storage.getSummary(id)
+	if err != nil {
+		return err
+	}
+
+	summary.SetResponse(response)
+
+	jsonData, err := json.Marshal(summary)
+	if err != nil {
+		log.Println("error serializing request summary", err)
+
+		return err
+	}
+
+	_, err = storage.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, storage.key("summary_%s", id), jsonData, 0)
+		pipe.ZAdd(ctx, storage.key(durationIndex), redis.Z{Score: float64(summary.Duration.Milliseconds()), Member: id})
+		pipe.ZAdd(ctx, storage.key(sizeIndex), redis.Z{Score: float64(summary.Size), Member: id})
+		pipe.SAdd(ctx, storage.statusIndex(summary.Code), id)
+
+		if summary.ContentType != "" {
+			pipe.SAdd(ctx, storage.contentTypeIndex(summary.ContentType), id)
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Println("error indexing response", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (storage *Storage) getSummary(id string) (*models.RequestSummary, error) {
+	data, err := storage.client.Get(context.Background(), storage.key("summary_%s", id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		log.Println("error getting request summary", err)
+
+		return nil, err
+	}
+
+	var summary models.RequestSummary
+
+	err = json.Unmarshal(data, &summary)
+	if err != nil {
+		log.Println("error deserializing request summary", err)
+
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+func (storage *Storage) getSummaries(ids []string) ([]*models.RequestSummary, error) {
+	if len(ids) == 0 {
+		return []*models.RequestSummary{}, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = storage.key("summary_%s", id)
+	}
+
+	values, err := storage.client.MGet(context.Background(), keys...).Result()
+	if err != nil {
+		log.Println("error getting request summaries", err)
+
+		return nil, err
+	}
+
+	summaries := make([]*models.RequestSummary, 0, len(values))
+
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var summary models.RequestSummary
+
+		err = json.Unmarshal([]byte(data), &summary)
+		if err != nil {
+			log.Println("error deserializing request summary", err)
+
+			continue
+		}
+
+		summaries = append(summaries, &summary)
+	}
+
+	return summaries, nil
+}
+
+func (storage *Storage) filterIndex(ctx context.Context, filter *models.RequestsFilter) (string, func(), error) {
+	sortKey := storage.sortIndex(filter.SortBy)
+
+	store := &redis.ZStore{
+		Keys:    []string{sortKey},
+		Weights: []float64{1},
+	}
+
+	if filter.Method != "" {
+		store.Keys = append(store.Keys, storage.methodIndex(filter.Method))
+	}
+
+	if filter.Host != "" {
+		store.Keys = append(store.Keys, storage.hostIndex(filter.Host))
+	}
+
+	if filter.StatusCode != 0 {
+		store.Keys = append(store.Keys, storage.statusIndex(filter.StatusCode))
+	}
+
+	if filter.ContentType != "" {
+		store.Keys = append(store.Keys, storage.contentTypeIndex(filter.ContentType))
+	}
+
+	if filter.ParentID != "" {
+		store.Keys = append(store.Keys, storage.parentIndex(filter.ParentID))
+	}
+
+	tmpKeys := make([]string, 0, 2)
+	cleanup := func() {
+		if len(tmpKeys) > 0 {
+			storage.client.Del(ctx, tmpKeys...)
+		}
+	}
+
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		timeKey := storage.tmpKey()
+		tmpKeys = append(tmpKeys, timeKey)
+
+		_, err := storage.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZUnionStore(ctx, timeKey, &redis.ZStore{Keys: []string{storage.key(timeIndex)}})
+
+			if !filter.From.IsZero() {
+				pipe.ZRemRangeByScore(ctx, timeKey, "-inf", fmt.Sprintf("(%d", filter.From.UnixMilli()))
+			}
+
+			if !filter.To.IsZero() {
+				pipe.ZRemRangeByScore(ctx, timeKey, fmt.Sprintf("(%d", filter.To.UnixMilli()), "+inf")
+			}
+
+			pipe.Expire(ctx, timeKey, tmpKeyTTL)
+
+			return nil
+		})
+		if err != nil {
+			log.Println("error filtering requests by time", err)
+			cleanup()
+
+			return "", nil, err
+		}
+
+		store.Keys = append(store.Keys, timeKey)
+	}
+
+	if len(store.Keys) == 1 {
+		return sortKey, cleanup, nil
+	}
+
+	for len(store.Weights) < len(store.Keys) {
+		store.Weights = append(store.Weights, 0)
+	}
+
+	resultKey := storage.tmpKey()
+	tmpKeys = append(tmpKeys, resultKey)
+
+	_, err := storage.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZInterStore(ctx, resultKey, store)
+		pipe.Expire(ctx, resultKey, tmpKeyTTL)
+
+		return nil
+	})
+	if err != nil {
+		log.Println("error filtering requests", err)
+		cleanup()
+
+		return "", nil, err
+	}
+
+	return resultKey, cleanup, nil
+}
+
+func (storage *Storage) tmpKey() string {
+	id, _ := storage.client.Incr(context.Background(), "next_tmp_key").Result()
+
+	return fmt.Sprintf("tmp:%d", id)
+}
+
+func (storage *Storage) rangeAfter(ctx context.Context, key string, after *models.Cursor,
+	desc bool, count int) ([]redis.Z, error) {
+	if after == nil {
+		return storage.client.ZRangeArgsWithScores(ctx, redis.ZRangeArgs{
+			Key:     key,
+			Start:   "-inf",
+			Stop:    "+inf",
+			ByScore: true,
+			Rev:     desc,
+			Count:   int64(count),
+		}).Result()
+	}
+
+	score := strconv.FormatFloat(after.Score, 'f', -1, 64)
+
+	ties, err := storage.client.ZRangeArgsWithScores(ctx, redis.ZRangeArgs{
+		Key:     key,
+		Start:   score,
+		Stop:    score,
+		ByScore: true,
+		Rev:     desc,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]redis.Z, 0, count)
+
+	for _, z := range ties {
+		member, _ := z.Member.(string)
+		if (!desc && member > after.ID) || (desc && member < after.ID) {
+			result = append(result, z)
+		}
+	}
+
+	if len(result) >= count {
+		return result[:count], nil
+	}
+
+	args := redis.ZRangeArgs{
+		Key:     key,
+		Start:   "(" + score,
+		Stop:    "+inf",
+		ByScore: true,
+		Rev:     desc,
+		Count:   int64(count - len(result)),
+	}
+	if desc {
+		args.Start, args.Stop = "-inf", "("+score
+	}
+
+	rest, err := storage.client.ZRangeArgsWithScores(ctx, args).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, rest...), nil
+}
+
+func (storage *Storage) ListRequests(filter *models.RequestsFilter) (*models.RequestsPage, error) {
+	ctx := context.Background()
+
+	after, err := models.DecodeCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	key, cleanup, err := storage.filterIndex(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = models.DefaultPageLimit
+	}
+
+	batchSize := max(limit+1, models.DefaultPageLimit)
+
+	selected := make([]redis.Z, 0, limit+1)
+
+	for len(selected) <= limit {
+		batch, err := storage.rangeAfter(ctx, key, after, filter.Desc, batchSize)
+		if err != nil {
+			log.Println("error listing requests", err)
+
+			return nil, err
+		}
+
+		if len(batch) == 0 {
+			break
+		}
+
+		paths := make(map[string]string)
+
+		if filter.PathGlob != "" {
+			ids := make([]string, len(batch))
+			for i, z := range batch {
+				ids[i], _ = z.Member.(string)
+			}
+
+			values, err := storage.client.HMGet(ctx, storage.key(pathIndex), ids...).Result()
+			if err != nil {
+				log.Println("error filtering requests by path", err)
+
+				return nil, err
+			}
+
+			for i, value := range values {
+				paths[ids[i]], _ = value.(string)
+			}
+		}
+
+		for _, z := range batch {
+			member, _ := z.Member.(string)
+
+			if filter.PathGlob == "" || filter.MatchPath(paths[member]) {
+				selected = append(selected, z)
+			}
+
+			if len(selected) > limit {
+				break
+			}
+		}
+
+		last := batch[len(batch)-1]
+		after = &models.Cursor{Score: last.Score}
+		after.ID, _ = last.Member.(string)
+
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	page := &models.RequestsPage{}
+
+	if len(selected) > limit {
+		selected = selected[:limit]
+		last := selected[limit-1]
+		page.NextCursor = models.EncodeCursor(last.Score, last.Member.(string))
+	}
+
+	ids := make([]string, len(selected))
+	for i, z := range selected {
+		ids[i], _ = z.Member.(string)
+	}
+
+	page.Requests, err = storage.getSummaries(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"proxy/internal/web-api/models"
+	"slices"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	issueKeysKey = "issue_keys"
+	issuesIndex  = "index:issues"
+)
+
+func (storage *Storage) SaveIssue(issue *models.Issue) error {
+	ctx := context.Background()
+
+	id, err := storage.client.HGet(ctx, storage.key(issueKeysKey), issue.Key()).Result()
+	if errors.Is(err, redis.Nil) {
+		var claimed bool
+
+		id, claimed, err = storage.claimIssueID(ctx, issue.Key())
+		if err != nil {
+			return err
+		}
+
+		if claimed {
+			issue.ID = id
+			issue.Count = max(issue.Count, 1)
+
+			return storage.storeIssue(ctx, issue)
+		}
+	} else if err != nil {
+		log.Println("error getting issue id", err)
+
+		return err
+	}
+
+	existing, err := storage.GetIssue(id)
+	if err != nil {
+		return err
+	}
+
+	existing.Merge(issue)
+	*issue = *existing
+
+	return storage.storeIssue(ctx, issue)
+}
+
+// claimIssueID registers a new id for the issue key. If another writer registered the key
+// first, its id is returned instead.
+func (storage *Storage) claimIssueID(ctx context.Context, key string) (string, bool, error) {
+	next, err := storage.client.Incr(ctx, storage.key("next_issue_key")).Result()
+	if err != nil {
+		log.Println("error getting next issue id", err)
+
+		return "", false, err
+	}
+
+	id := strconv.FormatInt(next, 10)
+
+	claimed, err := storage.client.HSetNX(ctx, storage.key(issueKeysKey), key, id).Result()
+	if err != nil {
+		log.Println("error saving issue id", err)
+
+		return "", false, err
+	}
+
+	if claimed {
+		return id, true, nil
+	}
+
+	id, err = storage.client.HGet(ctx, storage.key(issueKeysKey), key).Result()
+	if err != nil {
+		log.Println("error getting issue id", err)
+
+		return "", false, err
+	}
+
+	return id, false, nil
+}
+
+func (storage *Storage) storeIssue(ctx context.Context, issue *models.Issue) error {
+	jsonData, err := json.Marshal(issue)
+	if err != nil {
+		log.Println("error serializing issue", err)
+
+		return err
+	}
+
+	id, _ := strconv.Atoi(issue.ID)
+
+	_, err = storage.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, storage.key("issue_%s", issue.ID), jsonData, 0)
+		pipe.ZAdd(ctx, storage.key(issuesIndex), redis.Z{Score: float64(id), Member: issue.ID})
+
+		return nil
+	})
+	if err != nil {
+		log.Println("error saving issue", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (storage *Storage) GetIssue(id string) (*models.Issue, error) {
+	data, err := storage.client.Get(context.Background(), storage.key("issue_%s", id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, models.ErrIssueNotFound
+	}
+
+	if err != nil {
+		log.Println("error getting issue", err)
+
+		return nil, err
+	}
+
+	var issue models.Issue
+
+	err = json.Unmarshal(data, &issue)
+	if err != nil {
+		log.Println("error deserializing issue", err)
+
+		return nil, err
+	}
+
+	return &issue, nil
+}
+
+func (storage *Storage) ListIssues(filter *models.IssuesFilter) ([]*models.Issue, error) {
+	ctx := context.Background()
+	issues := make([]*models.Issue, 0)
+
+	ids, err := storage.client.ZRange(ctx, storage.key(issuesIndex), 0, -1).Result()
+	if err != nil {
+		log.Println("error getting issue ids", err)
+
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return issues, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = storage.key("issue_%s", id)
+	}
+
+	values, err := storage.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Println("error getting issues", err)
+
+		return nil, err
+	}
+
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var issue models.Issue
+
+		err = json.Unmarshal([]byte(data), &issue)
+		if err != nil {
+			log.Println("error deserializing issue", err)
+
+			continue
+		}
+
+		if filter.Match(&issue) {
+			issues = append(issues, &issue)
+		}
+	}
+
+	slices.SortFunc(issues, models.CompareIssues)
+
+	return issues, nil
+}
@@ -0,0 +1,107 @@
+package local
+
+import (
+	"errors"
+	"log"
+	"proxy/internal/web-api/models"
+	"slices"
+	"strconv"
+)
+
+const issuesKey = "issues"
+
+type storedIssues struct {
+	LastID int             `json:"last_id"`
+	Issues []*models.Issue `json:"issues"`
+}
+
+func (storage *Storage) issues() (*storedIssues, error) {
+	stored := &storedIssues{}
+
+	err := storage.blobs.GetValue(issuesKey, stored)
+	if errors.Is(err, models.ErrNotFound) {
+		return stored, nil
+	}
+
+	if err != nil {
+		log.Println("error getting issues", err)
+
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func (storage *Storage) SaveIssue(issue *models.Issue) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	stored, err := storage.issues()
+	if err != nil {
+		return err
+	}
+
+	index := slices.IndexFunc(stored.Issues, func(existing *models.Issue) bool {
+		return existing.Key() == issue.Key()
+	})
+
+	if index >= 0 {
+		existing := stored.Issues[index]
+		existing.Merge(issue)
+		*issue = *existing
+	} else {
+		stored.LastID++
+		issue.ID = strconv.Itoa(stored.LastID)
+		issue.Count = max(issue.Count, 1)
+		stored.Issues = append(stored.Issues, issue)
+	}
+
+	err = storage.blobs.SaveValue(issuesKey, stored)
+	if err != nil {
+		log.Println("error saving issue", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (storage *Storage) GetIssue(id string) (*models.Issue, error) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	stored, err := storage.issues()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range stored.Issues {
+		if issue.ID == id {
+			return issue, nil
+		}
+	}
+
+	return nil, models.ErrIssueNotFound
+}
+
+func (storage *Storage) ListIssues(filter *models.IssuesFilter) ([]*models.Issue, error) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	stored, err := storage.issues()
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]*models.Issue, 0, len(stored.Issues))
+
+	for _, issue := range stored.Issues {
+		if filter.Match(issue) {
+			issues = append(issues, issue)
+		}
+	}
+
+	slices.SortFunc(issues, models.CompareIssues)
+
+	return issues, nil
+}
@@ -0,0 +1,236 @@
+package local
+
+import (
+	"errors"
+	"log"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/usecases"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	projectsKey       = "projects"
+	currentProjectKey = "current_project"
+)
+
+type Opener func(name string) (*Storage, error)
+
+type Remover func(name string) error
+
+type Projects struct {
+	mu       sync.Mutex
+	open     Opener
+	remove   Remover
+	storages map[string]*Storage
+}
+
+func NewProjects(open Opener, remove Remover) (*Projects, error) {
+	projects := &Projects{
+		open:     open,
+		remove:   remove,
+		storages: make(map[string]*Storage),
+	}
+
+	_, err := projects.storage(models.DefaultProject)
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (projects *Projects) storage(name string) (*Storage, error) {
+	if storage, ok := projects.storages[name]; ok {
+		return storage, nil
+	}
+
+	storage, err := projects.open(name)
+	if err != nil {
+		log.Println("error opening project", name, err)
+
+		return nil, err
+	}
+
+	projects.storages[name] = storage
+
+	return storage, nil
+}
+
+func (projects *Projects) registry() *Storage {
+	return projects.storages[models.DefaultProject]
+}
+
+func (projects *Projects) projects() (map[string]*models.Project, error) {
+	registered := make(map[string]*models.Project)
+
+	err := projects.registry().blobs.GetValue(projectsKey, &registered)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		log.Println("error getting projects", err)
+
+		return nil, err
+	}
+
+	return registered, nil
+}
+
+func (projects *Projects) OpenProject(name string) (usecases.WebApiInterface, error) {
+	projects.mu.Lock()
+	defer projects.mu.Unlock()
+
+	storage, err := projects.storage(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage, nil
+}
+
+func (projects *Projects) ListProjects() ([]*models.Project, error) {
+	projects.mu.Lock()
+	defer projects.mu.Unlock()
+
+	registered, err := projects.projects()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.Project, 0, len(registered))
+	for _, project := range registered {
+		result = append(result, project)
+	}
+
+	slices.SortFunc(result, func(a, b *models.Project) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result, nil
+}
+
+func (projects *Projects) SaveProject(project *models.Project) error {
+	projects.mu.Lock()
+	defer projects.mu.Unlock()
+
+	registered, err := projects.projects()
+	if err != nil {
+		return err
+	}
+
+	registered[project.Name] = project
+
+	err = projects.registry().blobs.SaveValue(projectsKey, registered)
+	if err != nil {
+		log.Println("error saving project", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (projects *Projects) DeleteProject(name string) error {
+	if name == models.DefaultProject {
+		return models.ErrProjectProtected
+	}
+
+	projects.mu.Lock()
+	defer projects.mu.Unlock()
+
+	registered, err := projects.projects()
+	if err != nil {
+		return err
+	}
+
+	err = projects.remove(name)
+	if err != nil {
+		log.Println("error deleting project data", err)
+
+		return err
+	}
+
+	delete(projects.storages, name)
+	delete(registered, name)
+
+	err = projects.registry().blobs.SaveValue(projectsKey, registered)
+	if err != nil {
+		log.Println("error deleting project", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (projects *Projects) GetCurrentProject() (string, error) {
+	projects.mu.Lock()
+	defer projects.mu.Unlock()
+
+	var name string
+
+	err := projects.registry().blobs.GetValue(currentProjectKey, &name)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.DefaultProject, nil
+	}
+
+	if err != nil {
+		log.Println("error getting current project", err)
+
+		return "", err
+	}
+
+	return name, nil
+}
+
+func (projects *Projects) SaveCurrentProject(name string) error {
+	projects.mu.Lock()
+	defer projects.mu.Unlock()
+
+	err := projects.registry().blobs.SaveValue(currentProjectKey, name)
+	if err != nil {
+		log.Println("error saving current project", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (storage *Storage) RestoreTransaction(request *network.HTTPRequest, response *network.HTTPResponse) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	if _, ok := storage.summaries[request.ID]; ok {
+		err := storage.remove(request.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := storage.blobs.SaveRequest(request)
+	if err != nil {
+		log.Println("error restoring request", err)
+
+		return err
+	}
+
+	storage.addRequest(request)
+
+	if response != nil {
+		response.ID = request.ID
+
+		err = storage.blobs.SaveResponse(response)
+		if err != nil {
+			log.Println("error restoring response", err)
+
+			return err
+		}
+
+		storage.addResponse(response, request.ID)
+	}
+
+	storage.evict()
+
+	return nil
+}
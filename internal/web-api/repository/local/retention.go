@@ -0,0 +1,81 @@
+package local
+
+import (
+	"errors"
+	"log"
+	"proxy/internal/web-api/models"
+)
+
+const retentionPolicyKey = "retention_policy"
+
+func (storage *Storage) DeleteRequest(id string) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	if _, ok := storage.summaries[id]; !ok {
+		return models.ErrNotFound
+	}
+
+	err := storage.remove(id)
+	if err != nil {
+		log.Println("error deleting request", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (storage *Storage) DeleteAllRequests() (int, error) {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	deleted := 0
+
+	for _, id := range storage.orderedIDs() {
+		err := storage.remove(id)
+		if err != nil {
+			log.Println("error deleting history", err)
+
+			return deleted, err
+		}
+
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func (storage *Storage) GetRetentionPolicy() (*models.RetentionPolicy, error) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	policy := &models.RetentionPolicy{}
+
+	err := storage.blobs.GetValue(retentionPolicyKey, policy)
+	if errors.Is(err, models.ErrNotFound) {
+		return policy, nil
+	}
+
+	if err != nil {
+		log.Println("error getting retention policy", err)
+
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (storage *Storage) SaveRetentionPolicy(policy *models.RetentionPolicy) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	err := storage.blobs.SaveValue(retentionPolicyKey, policy)
+	if err != nil {
+		log.Println("error saving retention policy", err)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package local
+
+import (
+	"errors"
+	"log"
+	"proxy/internal/web-api/models"
+)
+
+const scopeKey = "scope"
+
+func (storage *Storage) GetScope() (*models.Scope, error) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	scope := &models.Scope{}
+
+	err := storage.blobs.GetValue(scopeKey, scope)
+	if errors.Is(err, models.ErrNotFound) {
+		return scope, nil
+	}
+
+	if err != nil {
+		log.Println("error getting scope", err)
+
+		return nil, err
+	}
+
+	return scope, nil
+}
+
+func (storage *Storage) SaveScope(scope *models.Scope) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	err := storage.blobs.SaveValue(scopeKey, scope)
+	if err != nil {
+		log.Println("error saving scope", err)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,430 @@
+package local
+
+import (
+	"container/list"
+	"errors"
+	"log"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/search"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+var ErrBadCapacity = errors.New("storage capacity must not be negative")
+
+type Blobs interface {
+	SaveRequest(request *network.HTTPRequest) error
+	SaveResponse(response *network.HTTPResponse) error
+	GetRequest(id string) (*network.HTTPRequest, error)
+	GetResponse(id string) (*network.HTTPResponse, error)
+	Delete(id string) error
+	IDs() ([]string, error)
+	SaveValue(key string, value any) error
+	GetValue(key string, value any) error
+}
+
+type Storage struct {
+	mu        sync.RWMutex
+	blobs     Blobs
+	capacity  int
+	lastID    int
+	ids       *list.List
+	elements  map[string]*list.Element
+	summaries map[string]*models.RequestSummary
+	trigrams  map[string]map[string]struct{}
+	docs      map[string][]string
+}
+
+// NewStorage keeps at most capacity requests, evicting the oldest first; zero means no limit.
+func NewStorage(blobs Blobs, capacity int) (*Storage, error) {
+	if capacity < 0 {
+		return nil, ErrBadCapacity
+	}
+
+	storage := &Storage{
+		blobs:     blobs,
+		capacity:  capacity,
+		ids:       list.New(),
+		elements:  make(map[string]*list.Element),
+		summaries: make(map[string]*models.RequestSummary),
+		trigrams:  make(map[string]map[string]struct{}),
+		docs:      make(map[string][]string),
+	}
+
+	ids, err := blobs.IDs()
+	if err != nil {
+		log.Println("error listing stored requests", err)
+
+		return nil, err
+	}
+
+	slices.SortFunc(ids, compareIDs)
+
+	for _, id := range ids {
+		err = storage.load(id)
+		if err != nil {
+			log.Println("error loading request", id, err)
+		}
+	}
+
+	storage.evict()
+
+	return storage, nil
+}
+
+func compareIDs(a, b string) int {
+	aInt, _ := strconv.Atoi(a)
+	bInt, _ := strconv.Atoi(b)
+
+	return aInt - bInt
+}
+
+func (storage *Storage) load(id string) error {
+	request, err := storage.blobs.GetRequest(id)
+	if err != nil {
+		return err
+	}
+
+	storage.addRequest(request)
+
+	response, err := storage.blobs.GetResponse(id)
+	if err == nil && response != nil {
+		storage.addResponse(response, id)
+	}
+
+	return nil
+}
+
+func (storage *Storage) addRequest(request *network.HTTPRequest) {
+	idInt, _ := strconv.Atoi(request.ID)
+	storage.lastID = max(storage.lastID, idInt)
+
+	storage.insertID(request.ID)
+	storage.summaries[request.ID] = models.NewRequestSummary(request)
+	storage.indexSearch(request.ID, search.RequestFields(request))
+}
+
+// insertID keeps ids in ascending order. New requests have the highest id, so the search from
+// the back ends at once for them.
+func (storage *Storage) insertID(id string) {
+	for element := storage.ids.Back(); element != nil; element = element.Prev() {
+		if compareIDs(element.Value.(string), id) < 0 {
+			storage.elements[id] = storage.ids.InsertAfter(id, element)
+
+			return
+		}
+	}
+
+	storage.elements[id] = storage.ids.PushFront(id)
+}
+
+func (storage *Storage) orderedIDs() []string {
+	ids := make([]string, 0, storage.ids.Len())
+
+	for element := storage.ids.Front(); element != nil; element = element.Next() {
+		ids = append(ids, element.Value.(string))
+	}
+
+	return ids
+}
+
+func (storage *Storage) addResponse(response *network.HTTPResponse, id string) {
+	summary, ok := storage.summaries[id]
+	if !ok {
+		return
+	}
+
+	summary.SetResponse(response)
+	storage.indexSearch(id, search.ResponseFields(response))
+}
+
+func (storage *Storage) indexSearch(id string, fields []search.Field) {
+	trigrams := search.Trigrams(fields)
+
+	for _, trigram := range trigrams {
+		postings, ok := storage.trigrams[trigram]
+		if !ok {
+			postings = make(map[string]struct{})
+			storage.trigrams[trigram] = postings
+		}
+
+		postings[id] = struct{}{}
+	}
+
+	storage.docs[id] = append(storage.docs[id], trigrams...)
+}
+
+func (storage *Storage) remove(id string) error {
+	err := storage.blobs.Delete(id)
+	if err != nil {
+		return err
+	}
+
+	for _, trigram := range storage.docs[id] {
+		delete(storage.trigrams[trigram], id)
+
+		if len(storage.trigrams[trigram]) == 0 {
+			delete(storage.trigrams, trigram)
+		}
+	}
+
+	delete(storage.docs, id)
+	delete(storage.summaries, id)
+
+	if element, ok := storage.elements[id]; ok {
+		storage.ids.Remove(element)
+		delete(storage.elements, id)
+	}
+
+	return nil
+}
+
+func (storage *Storage) evict() {
+	if storage.capacity <= 0 {
+		return
+	}
+
+	for storage.ids.Len() > storage.capacity {
+		err := storage.remove(storage.ids.Front().Value.(string))
+		if err != nil {
+			log.Println("error evicting request", err)
+
+			return
+		}
+	}
+}
+
+func (storage *Storage) SaveRequest(request *network.HTTPRequest) (string, error) {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	request.ID = strconv.Itoa(storage.lastID + 1)
+
+	err := storage.blobs.SaveRequest(request)
+	if err != nil {
+		log.Println("error saving request", err)
+
+		return "", err
+	}
+
+	storage.addRequest(request)
+	storage.evict()
+
+	return request.ID, nil
+}
+
+func (storage *Storage) SaveResponse(response *network.HTTPResponse, id string) error {
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+
+	if _, ok := storage.summaries[id]; !ok {
+		return models.ErrNotFound
+	}
+
+	response.ID = id
+
+	err := storage.blobs.SaveResponse(response)
+	if err != nil {
+		log.Println("error saving response", err)
+
+		return err
+	}
+
+	storage.addResponse(response, id)
+
+	return nil
+}
+
+func (storage *Storage) GetRequest(id string) (*network.HTTPRequest, error) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	if _, ok := storage.summaries[id]; !ok {
+		return nil, models.ErrNotFound
+	}
+
+	return storage.blobs.GetRequest(id)
+}
+
+func (storage *Storage) GetResponse(id string) (*network.HTTPResponse, error) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	if _, ok := storage.summaries[id]; !ok {
+		return nil, models.ErrNotFound
+	}
+
+	return storage.blobs.GetResponse(id)
+}
+
+func (storage *Storage) GetAllRequests() ([]*network.HTTPRequest, error) {
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	requests := make([]*network.HTTPRequest, 0, storage.ids.Len())
+
+	for _, id := range storage.orderedIDs() {
+		request, err := storage.blobs.GetRequest(id)
+		if err != nil {
+			continue
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+func (storage *Storage) ListRequests(filter *models.RequestsFilter) (*models.RequestsPage, error) {
+	after, err := models.DecodeCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = models.DefaultPageLimit
+	}
+
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	selected := make([]*models.RequestSummary, 0)
+
+	for _, id := range storage.orderedIDs() {
+		summary := storage.summaries[id]
+
+		if !filter.Match(summary) {
+			continue
+		}
+
+		if after != nil && !isAfter(summary, after, filter) {
+			continue
+		}
+
+		selected = append(selected, summary)
+	}
+
+	slices.SortFunc(selected, func(a, b *models.RequestSummary) int {
+		result := compareSummaries(a, b, filter.SortBy)
+		if filter.Desc {
+			return -result
+		}
+
+		return result
+	})
+
+	page := &models.RequestsPage{
+		Requests: make([]*models.RequestSummary, 0, min(limit, len(selected))),
+	}
+
+	if len(selected) > limit {
+		selected = selected[:limit]
+		last := selected[limit-1]
+		page.NextCursor = models.EncodeCursor(last.SortScore(filter.SortBy), last.ID)
+	}
+
+	for _, summary := range selected {
+		copied := *summary
+		page.Requests = append(page.Requests, &copied)
+	}
+
+	return page, nil
+}
+
+func compareSummaries(a, b *models.RequestSummary, sortBy string) int {
+	return compareKeys(a.SortScore(sortBy), a.ID, b.SortScore(sortBy), b.ID)
+}
+
+func compareKeys(aScore float64, aID string, bScore float64, bID string) int {
+	switch {
+	case aScore < bScore:
+		return -1
+	case aScore > bScore:
+		return 1
+	case aID < bID:
+		return -1
+	case aID > bID:
+		return 1
+	}
+
+	return 0
+}
+
+func isAfter(summary *models.RequestSummary, after *models.Cursor, filter *models.RequestsFilter) bool {
+	result := compareKeys(summary.SortScore(filter.SortBy), summary.ID, after.Score, after.ID)
+	if filter.Desc {
+		return result < 0
+	}
+
+	return result > 0
+}
+
+func (storage *Storage) Search(query *models.SearchQuery) ([]*models.SearchHit, error) {
+	matcher, err := search.NewMatcher(query)
+	if err != nil {
+		return nil, err
+	}
+
+	storage.mu.RLock()
+	defer storage.mu.RUnlock()
+
+	trigrams := search.QueryTrigrams(query)
+
+	var ids []string
+
+	if len(trigrams) == 0 {
+		ids = storage.orderedIDs()
+	} else {
+		truncated := make([]string, 0, len(storage.trigrams[search.Truncated]))
+		for id := range storage.trigrams[search.Truncated] {
+			truncated = append(truncated, id)
+		}
+
+		ids = search.Union(storage.intersect(trigrams), truncated)
+	}
+
+	search.SortNewestFirst(ids)
+
+	hits := search.Collect(ids, matcher, query.Limit, func(id string) (*network.HTTPRequest,
+		*network.HTTPResponse, error) {
+		request, err := storage.blobs.GetRequest(id)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		response, _ := storage.blobs.GetResponse(id)
+
+		return request, response, nil
+	})
+
+	return hits, nil
+}
+
+func (storage *Storage) intersect(trigrams []string) []string {
+	slices.SortFunc(trigrams, func(a, b string) int {
+		return len(storage.trigrams[a]) - len(storage.trigrams[b])
+	})
+
+	ids := make([]string, 0)
+
+	for id := range storage.trigrams[trigrams[0]] {
+		found := true
+
+		for _, trigram := range trigrams[1:] {
+			if _, ok := storage.trigrams[trigram][id]; !ok {
+				found = false
+
+				break
+			}
+		}
+
+		if found {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"encoding/json"
+	"log"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/repository/local"
+	"sync"
+)
+
+// blobs has its own lock: the project registry writes values of the default project while
+// holding only the registry lock.
+type blobs struct {
+	mu        sync.RWMutex
+	requests  map[string][]byte
+	responses map[string][]byte
+	values    map[string][]byte
+}
+
+func NewStorage(capacity int) (*local.Storage, error) {
+	return local.NewStorage(&blobs{
+		requests:  make(map[string][]byte),
+		responses: make(map[string][]byte),
+		values:    make(map[string][]byte),
+	}, capacity)
+}
+
+func (blobs *blobs) SaveRequest(request *network.HTTPRequest) error {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		log.Println("error serializing request ", err)
+
+		return err
+	}
+
+	blobs.mu.Lock()
+	blobs.requests[request.ID] = jsonData
+	blobs.mu.Unlock()
+
+	return nil
+}
+
+func (blobs *blobs) SaveResponse(response *network.HTTPResponse) error {
+	jsonData, err := json.Marshal(response)
+	if err != nil {
+		log.Println("error serializing response ", err)
+
+		return err
+	}
+
+	blobs.mu.Lock()
+	blobs.responses[response.ID] = jsonData
+	blobs.mu.Unlock()
+
+	return nil
+}
+
+func (blobs *blobs) GetRequest(id string) (*network.HTTPRequest, error) {
+	blobs.mu.RLock()
+	defer blobs.mu.RUnlock()
+
+	data, ok := blobs.requests[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+
+	var parsedReq network.HTTPRequest
+
+	err := json.Unmarshal(data, &parsedReq)
+	if err != nil {
+		log.Println("error deserializing request ", err)
+
+		return nil, err
+	}
+
+	return &parsedReq, nil
+}
+
+func (blobs *blobs) GetResponse(id string) (*network.HTTPResponse, error) {
+	blobs.mu.RLock()
+	defer blobs.mu.RUnlock()
+
+	data, ok := blobs.responses[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+
+	var parsedResp network.HTTPResponse
+
+	err := json.Unmarshal(data, &parsedResp)
+	if err != nil {
+		log.Println("error deserializing response ", err)
+
+		return nil, err
+	}
+
+	return &parsedResp, nil
+}
+
+func (blobs *blobs) Delete(id string) error {
+	blobs.mu.Lock()
+	defer blobs.mu.Unlock()
+
+	delete(blobs.requests, id)
+	delete(blobs.responses, id)
+
+	return nil
+}
+
+func (blobs *blobs) IDs() ([]string, error) {
+	blobs.mu.RLock()
+	defer blobs.mu.RUnlock()
+
+	ids := make([]string, 0, len(blobs.requests))
+	for id := range blobs.requests {
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (blobs *blobs) SaveValue(key string, value any) error {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		log.Println("error serializing", key, err)
+
+		return err
+	}
+
+	blobs.mu.Lock()
+	blobs.values[key] = jsonData
+	blobs.mu.Unlock()
+
+	return nil
+}
+
+func (blobs *blobs) GetValue(key string, value any) error {
+	blobs.mu.RLock()
+	defer blobs.mu.RUnlock()
+
+	data, ok := blobs.values[key]
+	if !ok {
+		return models.ErrNotFound
+	}
+
+	return json.Unmarshal(data, value)
+}
+
+func NewProjects(capacity int) (*local.Projects, error) {
+	return local.NewProjects(func(name string) (*local.Storage, error) {
+		return NewStorage(capacity)
+	}, func(name string) error {
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"errors"
+	"net/http"
+	"proxy/internal/network"
+	"proxy/internal/web-api/repository/local"
+	"proxy/internal/web-api/repository/storagetest"
+	"proxy/internal/web-api/usecases"
+	"slices"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) usecases.WebApiInterface {
+		storage, err := NewStorage(100)
+		if err != nil {
+			t.Fatalf("NewStorage: %v", err)
+		}
+
+		return storage
+	})
+}
+
+func TestStorageBadCapacity(t *testing.T) {
+	_, err := NewStorage(-1)
+	if !errors.Is(err, local.ErrBadCapacity) {
+		t.Errorf("NewStorage(-1) error = %v, want %v", err, local.ErrBadCapacity)
+	}
+}
+
+func TestStorageEvictsOldest(t *testing.T) {
+	storage, err := NewStorage(3)
+	if err != nil {
+		t.Fatalf("NewStorage: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err := storage.SaveRequest(&network.HTTPRequest{Method: "GET", Host: "example.com", Header: http.Header{}})
+		if err != nil {
+			t.Fatalf("SaveRequest: %v", err)
+		}
+	}
+
+	err = storage.RestoreTransaction(&network.HTTPRequest{ID: "4", Method: "PUT", Host: "example.com",
+		Header: http.Header{}}, nil)
+	if err != nil {
+		t.Fatalf("RestoreTransaction: %v", err)
+	}
+
+	requests, err := storage.GetAllRequests()
+	if err != nil {
+		t.Fatalf("GetAllRequests: %v", err)
+	}
+
+	ids := make([]string, 0, len(requests))
+	for _, request := range requests {
+		ids = append(ids, request.ID)
+	}
+
+	if want := []string{"3", "4", "5"}; !slices.Equal(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/usecases"
+	"slices"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	projectsKey       = "projects"
+	currentProjectKey = "current_project"
+)
+
+var bumpKeyScript = redis.NewScript(`
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+if current < tonumber(ARGV[1]) then
+	redis.call("SET", KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+type Projects struct {
+	client *redis.Client
+}
+
+func NewProjects(client *redis.Client) *Projects {
+	return &Projects{client: client}
+}
+
+func projectPrefix(name string) string {
+	if name == models.DefaultProject {
+		return ""
+	}
+
+	return fmt.Sprintf("project:%s:", name)
+}
+
+func (projects *Projects) OpenProject(name string) (usecases.WebApiInterface, error) {
+	return newProjectStorage(projects.client, projectPrefix(name)), nil
+}
+
+func (projects *Projects) ListProjects() ([]*models.Project, error) {
+	values, err := projects.client.HGetAll(context.Background(), projectsKey).Result()
+	if err != nil {
+		log.Println("error getting projects", err)
+
+		return nil, err
+	}
+
+	result := make([]*models.Project, 0, len(values))
+
+	for _, value := range values {
+		var project models.Project
+
+		err = json.Unmarshal([]byte(value), &project)
+		if err != nil {
+			log.Println("error deserializing project", err)
+
+			continue
+		}
+
+		result = append(result, &project)
+	}
+
+	slices.SortFunc(result, func(a, b *models.Project) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result, nil
+}
+
+func (projects *Projects) SaveProject(project *models.Project) error {
+	jsonData, err := json.Marshal(project)
+	if err != nil {
+		log.Println("error serializing project", err)
+
+		return err
+	}
+
+	err = projects.client.HSet(context.Background(), projectsKey, project.Name, jsonData).Err()
+	if err != nil {
+		log.Println("error saving project", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (projects *Projects) DeleteProject(name string) error {
+	if name == models.DefaultProject {
+		return models.ErrProjectProtected
+	}
+
+	ctx := context.Background()
+
+	storage := newProjectStorage(projects.client, projectPrefix(name))
+
+	err := storage.deleteByPattern(ctx, storage.key("*"))
+	if err != nil {
+		log.Println("error deleting project data", err)
+
+		return err
+	}
+
+	err = projects.client.HDel(ctx, projectsKey, name).Err()
+	if err != nil {
+		log.Println("error deleting project", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (projects *Projects) GetCurrentProject() (string, error) {
+	name, err := projects.client.Get(context.Background(), currentProjectKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return models.DefaultProject, nil
+	}
+
+	if err != nil {
+		log.Println("error getting current project", err)
+
+		return "", err
+	}
+
+	return name, nil
+}
+
+func (projects *Projects) SaveCurrentProject(name string) error {
+	err := projects.client.Set(context.Background(), currentProjectKey, name, 0).Err()
+	if err != nil {
+		log.Println("error saving current project", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (storage *Storage) RestoreTransaction(request *network.HTTPRequest, response *network.HTTPResponse) error {
+	err := bumpKeyScript.Run(context.Background(), storage.client, []string{storage.key("next_key")}, request.ID).
+		Err()
+	if err != nil {
+		log.Println("error restoring request id", err)
+
+		return err
+	}
+
+	err = storage.storeRequest(request)
+	if err != nil {
+		return err
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	return storage.SaveResponse(response, request.ID)
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/search"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	retentionPolicyKey = "retention_policy"
+
+	scanBatchSize = 1000
+)
+
+var historyPatterns = []string{"request_*", "response_*", "summary_*", "index:*", "search:*"}
+
+func (storage *Storage) DeleteRequest(id string) error {
+	ctx := context.Background()
+
+	summary, err := storage.getSummary(id)
+	if err != nil {
+		return err
+	}
+
+	req, resp, err := storage.getTransaction(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	fields := make([]search.Field, 0, 4)
+	if req != nil {
+		fields = append(fields, search.RequestFields(req)...)
+	}
+
+	if resp != nil {
+		fields = append(fields, search.ResponseFields(resp)...)
+	}
+
+	_, err = storage.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, storage.key("request_%s", id), storage.key("response_%s", id), storage.key("summary_%s", id))
+
+		for _, index := range []string{idIndex, timeIndex, durationIndex, sizeIndex} {
+			pipe.ZRem(ctx, storage.key(index), id)
+		}
+
+		pipe.SRem(ctx, storage.methodIndex(summary.Method), id)
+		pipe.SRem(ctx, storage.hostIndex(summary.Host), id)
+		pipe.HDel(ctx, storage.key(pathIndex), id)
+
+		if summary.Code != 0 {
+			pipe.SRem(ctx, storage.statusIndex(summary.Code), id)
+		}
+
+		if summary.ContentType != "" {
+			pipe.SRem(ctx, storage.contentTypeIndex(summary.ContentType), id)
+		}
+
+		if summary.ParentID != "" {
+			pipe.SRem(ctx, storage.parentIndex(summary.ParentID), id)
+		}
+
+		for _, trigram := range search.Trigrams(fields) {
+			pipe.SRem(ctx, storage.searchKey(trigram), id)
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Println("error deleting request", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (storage *Storage) DeleteAllRequests() (int, error) {
+	ctx := context.Background()
+
+	count, err := storage.client.ZCard(ctx, storage.key(idIndex)).Result()
+	if err != nil {
+		log.Println("error counting history", err)
+
+		return 0, err
+	}
+
+	for _, pattern := range historyPatterns {
+		err = storage.deleteByPattern(ctx, storage.key(pattern))
+		if err != nil {
+			log.Println("error deleting history", err)
+
+			return 0, err
+		}
+	}
+
+	return int(count), nil
+}
+
+func (storage *Storage) deleteByPattern(ctx context.Context, pattern string) error {
+	var cursor uint64
+
+	for {
+		keys, next, err := storage.client.Scan(ctx, cursor, pattern, scanBatchSize).Result()
+		if err != nil {
+			return err
+		}
+
+		if len(keys) > 0 {
+			err = storage.client.Del(ctx, keys...).Err()
+			if err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+
+		cursor = next
+	}
+}
+
+func (storage *Storage) GetRetentionPolicy() (*models.RetentionPolicy, error) {
+	policy := &models.RetentionPolicy{}
+
+	data, err := storage.client.Get(context.Background(), storage.key(retentionPolicyKey)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return policy, nil
+	}
+
+	if err != nil {
+		log.Println("error getting retention policy", err)
+
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, policy)
+	if err != nil {
+		log.Println("error deserializing retention policy", err)
+
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (storage *Storage) SaveRetentionPolicy(policy *models.RetentionPolicy) error {
+	jsonData, err := json.Marshal(policy)
+	if err != nil {
+		log.Println("error serializing retention policy", err)
+
+		return err
+	}
+
+	err = storage.client.Set(context.Background(), storage.key(retentionPolicyKey), jsonData, 0).Err()
+	if err != nil {
+		log.Println("error saving retention policy", err)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"proxy/internal/web-api/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const scopeKey = "scope"
+
+func (storage *Storage) GetScope() (*models.Scope, error) {
+	scope := &models.Scope{}
+
+	data, err := storage.client.Get(context.Background(), storage.key(scopeKey)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return scope, nil
+	}
+
+	if err != nil {
+		log.Println("error getting scope", err)
+
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, scope)
+	if err != nil {
+		log.Println("error deserializing scope", err)
+
+		return nil, err
+	}
+
+	return scope, nil
+}
+
+func (storage *Storage) SaveScope(scope *models.Scope) error {
+	jsonData, err := json.Marshal(scope)
+	if err != nil {
+		log.Println("error serializing scope", err)
+
+		return err
+	}
+
+	err = storage.client.Set(context.Background(), storage.key(scopeKey), jsonData, 0).Err()
+	if err != nil {
+		log.Println("error saving scope", err)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/search"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func (storage *Storage) searchKey(trigram string) string {
+	return storage.key("search:%s", trigram)
+}
+
+func (storage *Storage) indexSearch(id string, fields []search.Field) error {
+	trigrams := search.Trigrams(fields)
+	if len(trigrams) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	_, err := storage.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, trigram := range trigrams {
+			pipe.SAdd(ctx, storage.searchKey(trigram), id)
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Println("error updating search index", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (storage *Storage) searchCandidates(ctx context.Context, query *models.SearchQuery) ([]string, error) {
+	trigrams := search.QueryTrigrams(query)
+
+	var (
+		ids []string
+		err error
+	)
+
+	if len(trigrams) == 0 {
+		ids, err = storage.client.ZRange(ctx, storage.key(idIndex), 0, -1).Result()
+	} else {
+		keys := make([]string, len(trigrams))
+		for i, trigram := range trigrams {
+			keys[i] = storage.searchKey(trigram)
+		}
+
+		ids, err = storage.client.SInter(ctx, keys...).Result()
+		if err == nil {
+			var truncated []string
+
+			truncated, err = storage.client.SMembers(ctx, storage.searchKey(search.Truncated)).Result()
+			ids = search.Union(ids, truncated)
+		}
+	}
+
+	if err != nil {
+		log.Println("error getting search candidates", err)
+
+		return nil, err
+	}
+
+	search.SortNewestFirst(ids)
+
+	return ids, nil
+}
+
+func (storage *Storage) getTransaction(ctx context.Context, id string) (*network.HTTPRequest,
+	*network.HTTPResponse, error) {
+	values, err := storage.client.MGet(ctx, storage.key("request_%s", id), storage.key("response_%s", id)).
+		Result()
+	if err != nil {
+		log.Println("error getting transaction", err)
+
+		return nil, nil, err
+	}
+
+	var (
+		req  *network.HTTPRequest
+		resp *network.HTTPResponse
+	)
+
+	if data, ok := values[0].(string); ok {
+		req = &network.HTTPRequest{}
+
+		err = json.Unmarshal([]byte(data), req)
+		if err != nil {
+			log.Println("error deserializing request ", err)
+
+			return nil, nil, err
+		}
+	}
+
+	if data, ok := values[1].(string); ok {
+		resp = &network.HTTPResponse{}
+
+		err = json.Unmarshal([]byte(data), resp)
+		if err != nil {
+			log.Println("error deserializing response ", err)
+
+			return nil, nil, err
+		}
+	}
+
+	return req, resp, nil
+}
+
+func (storage *Storage) Search(query *models.SearchQuery) ([]*models.SearchHit, error) {
+	matcher, err := search.NewMatcher(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	ids, err := storage.searchCandidates(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := search.Collect(ids, matcher, query.Limit, func(id string) (*network.HTTPRequest,
+		*network.HTTPResponse, error) {
+		return storage.getTransaction(ctx, id)
+	})
+
+	return hits, nil
+}
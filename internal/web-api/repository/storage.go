@@ -3,24 +3,43 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"log"
 	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/search"
 	"strconv"
 )
 
 type Storage struct {
 	client *redis.Client
+	prefix string
 }
 
 func NewStorage(client *redis.Client) *Storage {
-	client.SetNX(context.Background(), "next_key", 0, 0)
-	return &Storage{client: client}
+	return newProjectStorage(client, "")
+}
+
+func newProjectStorage(client *redis.Client, prefix string) *Storage {
+	storage := &Storage{client: client, prefix: prefix}
+	client.SetNX(context.Background(), storage.key("next_key"), 0, 0)
+
+	err := storage.backfill()
+	if err != nil {
+		log.Println("error indexing stored requests", err)
+	}
+
+	return storage
+}
+
+func (storage *Storage) key(format string, args ...any) string {
+	return storage.prefix + fmt.Sprintf(format, args...)
 }
 
 func (storage *Storage) nextKey() (string, error) {
-	id, err := storage.client.Incr(context.Background(), "next_key").Result()
+	id, err := storage.client.Incr(context.Background(), storage.key("next_key")).Result()
 	if err != nil {
 		log.Println("storage nextKey error:", err)
 
@@ -40,22 +59,36 @@ func (storage *Storage) SaveRequest(request *network.HTTPRequest) (string, error
 		return "", err
 	}
 
+	err = storage.storeRequest(request)
+	if err != nil {
+		return "", err
+	}
+
+	return request.ID, nil
+}
+
+func (storage *Storage) storeRequest(request *network.HTTPRequest) error {
 	jsonData, err := json.Marshal(request)
 	if err != nil {
 		log.Println("error serializing request ", err)
 
-		return "", err
+		return err
 	}
 
-	err = storage.client.Set(context.Background(), fmt.Sprintf("request_%s", request.ID), jsonData, 0).
+	err = storage.client.Set(context.Background(), storage.key("request_%s", request.ID), jsonData, 0).
 		Err()
 	if err != nil {
 		log.Println("error saving request", err)
 
-		return "", err
+		return err
 	}
 
-	return request.ID, nil
+	err = storage.indexRequest(request)
+	if err != nil {
+		return err
+	}
+
+	return storage.indexSearch(request.ID, search.RequestFields(request))
 }
 
 func (storage *Storage) SaveResponse(response *network.HTTPResponse, id string) error {
@@ -68,22 +101,36 @@ func (storage *Storage) SaveResponse(response *network.HTTPResponse, id string)
 		return err
 	}
 
-	err = storage.client.Set(context.Background(), fmt.Sprintf("response_%s", id), jsonData, 0).Err()
+	err = storage.client.Set(context.Background(), storage.key("response_%s", id), jsonData, 0).Err()
 	if err != nil {
 		log.Println("error saving response", err)
 
 		return err
 	}
 
-	return nil
+	err = storage.indexResponse(response, id)
+	if err != nil {
+		return err
+	}
+
+	return storage.indexSearch(id, search.ResponseFields(response))
 }
 
 func (storage *Storage) GetRequest(id string) (*network.HTTPRequest, error) {
-	request, _ := storage.client.Get(context.Background(), fmt.Sprintf("request_%s", id)).Bytes()
+	request, err := storage.client.Get(context.Background(), storage.key("request_%s", id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		log.Println("error getting request", err)
+
+		return nil, err
+	}
 
 	var parsedReq network.HTTPRequest
 
-	err := json.Unmarshal(request, &parsedReq)
+	err = json.Unmarshal(request, &parsedReq)
 	if err != nil {
 		log.Println("error deserializing request ", err)
 
@@ -94,11 +141,20 @@ func (storage *Storage) GetRequest(id string) (*network.HTTPRequest, error) {
 }
 
 func (storage *Storage) GetResponse(id string) (*network.HTTPResponse, error) {
-	response, _ := storage.client.Get(context.Background(), fmt.Sprintf("response_%s", id)).Bytes()
+	response, err := storage.client.Get(context.Background(), storage.key("response_%s", id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, models.ErrNotFound
+	}
+
+	if err != nil {
+		log.Println("error getting response", err)
+
+		return nil, err
+	}
 
 	var parsedResp network.HTTPResponse
 
-	err := json.Unmarshal(response, &parsedResp)
+	err = json.Unmarshal(response, &parsedResp)
 	if err != nil {
 		log.Println("error deserializing response ", err)
 
@@ -109,22 +165,46 @@ func (storage *Storage) GetResponse(id string) (*network.HTTPResponse, error) {
 }
 
 func (storage *Storage) GetAllRequests() ([]*network.HTTPRequest, error) {
-	lastID, _ := storage.client.Get(context.Background(), "next_key").Result()
-	lastIDInt, _ := strconv.Atoi(lastID)
+	ctx := context.Background()
 
-	requests := make([]*network.HTTPRequest, 0)
+	ids, err := storage.client.ZRange(ctx, storage.key(idIndex), 0, -1).Result()
+	if err != nil {
+		log.Println("error getting requests ids", err)
 
-	for id := 1; id <= lastIDInt; id++ {
-		request, _ := storage.client.Get(context.Background(), fmt.Sprintf("request_%s", strconv.Itoa(id))).
-			Bytes()
+		return nil, err
+	}
+
+	requests := make([]*network.HTTPRequest, 0, len(ids))
+
+	if len(ids) == 0 {
+		return requests, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = storage.key("request_%s", id)
+	}
+
+	values, err := storage.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Println("error getting requests", err)
+
+		return nil, err
+	}
+
+	for _, value := range values {
+		request, ok := value.(string)
+		if !ok {
+			continue
+		}
 
 		var parsedReq network.HTTPRequest
 
-		err := json.Unmarshal(request, &parsedReq)
+		err = json.Unmarshal([]byte(request), &parsedReq)
 		if err != nil {
 			log.Println("error deserializing request ", err)
 
-			return nil, err
+			continue
 		}
 
 		requests = append(requests, &parsedReq)
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"fmt"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/repository/storagetest"
+	"proxy/internal/web-api/usecases"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) usecases.WebApiInterface {
+		server := miniredis.RunT(t)
+
+		return NewStorage(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	})
+}
+
+func TestStorageIndexesLegacyRequests(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	// Requests and responses as written before the indices were kept.
+	for i := 1; i <= 3; i++ {
+		server.Set(fmt.Sprintf("request_%d", i), fmt.Sprintf(`{"id":"","proto":"HTTP/1.1","method":"GET",`+
+			`"scheme":"http","host":"legacy.test","port":"80","path":"/old/%d","headers":{},`+
+			`"body":"Ym9keQ=="}`, i))
+	}
+
+	server.Set("response_2", `{"id":"2","code":200,"message":"200 OK","proto":"HTTP/1.1","headers":{},"body":"old page"}`)
+	server.Set("next_key", "3")
+
+	storage := NewStorage(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+
+	requests, err := storage.GetAllRequests()
+	if err != nil {
+		t.Fatalf("GetAllRequests: %v", err)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("GetAllRequests returned %d requests, want 3", len(requests))
+	}
+
+	page, err := storage.ListRequests(&models.RequestsFilter{SortBy: models.SortBySize, Desc: true})
+	if err != nil {
+		t.Fatalf("ListRequests: %v", err)
+	}
+
+	if len(page.Requests) != 3 || page.Requests[0].ID != "2" {
+		t.Errorf("ListRequests returned %d requests, want 3 with the answered one first", len(page.Requests))
+	}
+
+	hits, err := storage.Search(&models.SearchQuery{Query: "old page", Mode: models.SearchModeLiteral, Limit: 10})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if len(hits) != 1 || hits[0].ID != "2" {
+		t.Errorf("Search found %d hits, want request 2", len(hits))
+	}
+}
@@ -0,0 +1,691 @@
+package storagetest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"proxy/internal/web-api/usecases"
+	"slices"
+	"testing"
+	"time"
+)
+
+type Factory func(t *testing.T) usecases.WebApiInterface
+
+func Run(t *testing.T, newStorage Factory) {
+	t.Run("SaveAndGet", func(t *testing.T) { testSaveAndGet(t, newStorage(t)) })
+	t.Run("BinaryBodies", func(t *testing.T) { testBinaryBodies(t, newStorage(t)) })
+	t.Run("MissingRequest", func(t *testing.T) { testMissingRequest(t, newStorage(t)) })
+	t.Run("GetAllRequests", func(t *testing.T) { testGetAllRequests(t, newStorage(t)) })
+	t.Run("ListPagination", func(t *testing.T) { testListPagination(t, newStorage(t)) })
+	t.Run("ListFilters", func(t *testing.T) { testListFilters(t, newStorage(t)) })
+	t.Run("ListSorting", func(t *testing.T) { testListSorting(t, newStorage(t)) })
+	t.Run("ListChildren", func(t *testing.T) { testListChildren(t, newStorage(t)) })
+	t.Run("Search", func(t *testing.T) { testSearch(t, newStorage(t)) })
+	t.Run("SearchLargeBody", func(t *testing.T) { testSearchLargeBody(t, newStorage(t)) })
+	t.Run("DeleteRequest", func(t *testing.T) { testDeleteRequest(t, newStorage(t)) })
+	t.Run("DeleteAllRequests", func(t *testing.T) { testDeleteAllRequests(t, newStorage(t)) })
+	t.Run("RetentionPolicy", func(t *testing.T) { testRetentionPolicy(t, newStorage(t)) })
+	t.Run("Scope", func(t *testing.T) { testScope(t, newStorage(t)) })
+	t.Run("Issues", func(t *testing.T) { testIssues(t, newStorage(t)) })
+	t.Run("RestoreTransaction", func(t *testing.T) { testRestoreTransaction(t, newStorage(t)) })
+}
+
+var baseTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newRequest(i int) *network.HTTPRequest {
+	method := http.MethodGet
+	if i%2 == 0 {
+		method = http.MethodPost
+	}
+
+	return &network.HTTPRequest{
+		Proto:     "HTTP/1.1",
+		Method:    method,
+		Scheme:    "http",
+		Host:      fmt.Sprintf("host%d.test", i%3),
+		Port:      "80",
+		Path:      fmt.Sprintf("/api/items/%d", i),
+		Header:    http.Header{"X-Request": {fmt.Sprintf("request-%d", i)}},
+		GetParams: url.Values{"page": {fmt.Sprint(i)}},
+		Body:      []byte(fmt.Sprintf("body of request %d", i)),
+		Time:      baseTime.Add(time.Duration(i) * time.Minute),
+	}
+}
+
+func newResponse(i int) *network.HTTPResponse {
+	body := fmt.Sprintf("<html>response number %d secret%04d</html>", i, i*7)
+
+	return &network.HTTPResponse{
+		Code:     200 + (i%2)*204,
+		Message:  "200 OK",
+		Proto:    "HTTP/1.1",
+		Headers:  http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:     []byte(body),
+		Duration: time.Duration(10-i) * time.Millisecond,
+	}
+}
+
+func populate(t *testing.T, storage usecases.WebApiInterface, count int) []string {
+	t.Helper()
+
+	ids := make([]string, 0, count)
+
+	for i := 1; i <= count; i++ {
+		id, err := storage.SaveRequest(newRequest(i))
+		if err != nil {
+			t.Fatalf("SaveRequest(%d): %v", i, err)
+		}
+
+		err = storage.SaveResponse(newResponse(i), id)
+		if err != nil {
+			t.Fatalf("SaveResponse(%s): %v", id, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func listAll(t *testing.T, storage usecases.WebApiInterface, filter models.RequestsFilter) []string {
+	t.Helper()
+
+	ids := make([]string, 0)
+
+	for pages := 0; ; pages++ {
+		if pages > 100 {
+			t.Fatalf("ListRequests did not terminate")
+		}
+
+		page, err := storage.ListRequests(&filter)
+		if err != nil {
+			t.Fatalf("ListRequests: %v", err)
+		}
+
+		for _, summary := range page.Requests {
+			ids = append(ids, summary.ID)
+		}
+
+		if page.NextCursor == "" {
+			return ids
+		}
+
+		filter.Cursor = page.NextCursor
+	}
+}
+
+func testSaveAndGet(t *testing.T, storage usecases.WebApiInterface) {
+	id, err := storage.SaveRequest(newRequest(1))
+	if err != nil {
+		t.Fatalf("SaveRequest: %v", err)
+	}
+
+	if id == "" {
+		t.Fatalf("SaveRequest returned empty id")
+	}
+
+	err = storage.SaveResponse(newResponse(1), id)
+	if err != nil {
+		t.Fatalf("SaveResponse: %v", err)
+	}
+
+	req, err := storage.GetRequest(id)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+
+	want := newRequest(1)
+	if req.ID != id || req.Method != want.Method || req.Host != want.Host || req.Path != want.Path ||
+		string(req.Body) != string(want.Body) || req.Header.Get("X-Request") != "request-1" ||
+		req.GetParams.Get("page") != "1" || !req.Time.Equal(want.Time) {
+		t.Errorf("GetRequest returned %+v, want %+v", req, want)
+	}
+
+	resp, err := storage.GetResponse(id)
+	if err != nil {
+		t.Fatalf("GetResponse: %v", err)
+	}
+
+	wantResp := newResponse(1)
+	if resp.ID != id || resp.Code != wantResp.Code || string(resp.Body) != string(wantResp.Body) ||
+		resp.Headers.Get("Content-Type") != wantResp.Headers.Get("Content-Type") ||
+		resp.Duration != wantResp.Duration {
+		t.Errorf("GetResponse returned %+v, want %+v", resp, wantResp)
+	}
+}
+
+func testBinaryBodies(t *testing.T, storage usecases.WebApiInterface) {
+	body := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, 0xfe, '\r', '\n', 0x1a}
+
+	req := newRequest(1)
+	req.Body = body
+
+	id, err := storage.SaveRequest(req)
+	if err != nil {
+		t.Fatalf("SaveRequest: %v", err)
+	}
+
+	resp := newResponse(1)
+	resp.Headers = http.Header{"Content-Type": {"image/png"}}
+	resp.Body = body
+
+	err = storage.SaveResponse(resp, id)
+	if err != nil {
+		t.Fatalf("SaveResponse: %v", err)
+	}
+
+	gotReq, err := storage.GetRequest(id)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+
+	if string(gotReq.Body) != string(body) {
+		t.Errorf("GetRequest body = %q, want %q", gotReq.Body, body)
+	}
+
+	gotResp, err := storage.GetResponse(id)
+	if err != nil {
+		t.Fatalf("GetResponse: %v", err)
+	}
+
+	if string(gotResp.Body) != string(body) {
+		t.Errorf("GetResponse body = %q, want %q", gotResp.Body, body)
+	}
+}
+
+func testMissingRequest(t *testing.T, storage usecases.WebApiInterface) {
+	populate(t, storage, 1)
+
+	if req, err := storage.GetRequest("12345"); err == nil {
+		t.Errorf("GetRequest(missing) = %+v, want error", req)
+	}
+
+	if resp, err := storage.GetResponse("12345"); err == nil {
+		t.Errorf("GetResponse(missing) = %+v, want error", resp)
+	}
+}
+
+func testGetAllRequests(t *testing.T, storage usecases.WebApiInterface) {
+	ids := populate(t, storage, 5)
+
+	requests, err := storage.GetAllRequests()
+	if err != nil {
+		t.Fatalf("GetAllRequests: %v", err)
+	}
+
+	got := make([]string, 0, len(requests))
+	for _, req := range requests {
+		got = append(got, req.ID)
+	}
+
+	if !slices.Equal(got, ids) {
+		t.Errorf("GetAllRequests ids = %v, want %v", got, ids)
+	}
+}
+
+func testListPagination(t *testing.T, storage usecases.WebApiInterface) {
+	ids := populate(t, storage, 7)
+
+	page, err := storage.ListRequests(&models.RequestsFilter{SortBy: models.SortByID, Limit: 3})
+	if err != nil {
+		t.Fatalf("ListRequests: %v", err)
+	}
+
+	if len(page.Requests) != 3 || page.NextCursor == "" {
+		t.Fatalf("first page has %d requests and cursor %q", len(page.Requests), page.NextCursor)
+	}
+
+	got := listAll(t, storage, models.RequestsFilter{SortBy: models.SortByID, Limit: 3})
+	if !slices.Equal(got, ids) {
+		t.Errorf("paginated ids = %v, want %v", got, ids)
+	}
+
+	_, err = storage.ListRequests(&models.RequestsFilter{Cursor: "not a cursor!", Limit: 3})
+	if err == nil {
+		t.Errorf("ListRequests with invalid cursor succeeded")
+	}
+}
+
+func testListFilters(t *testing.T, storage usecases.WebApiInterface) {
+	ids := populate(t, storage, 9)
+
+	tests := []struct {
+		name   string
+		filter models.RequestsFilter
+		want   []string
+	}{
+		{"method", models.RequestsFilter{Method: "post"}, []string{ids[1], ids[3], ids[5], ids[7]}},
+		{"host", models.RequestsFilter{Host: "host0.test"}, []string{ids[2], ids[5], ids[8]}},
+		{"path", models.RequestsFilter{PathGlob: "/api/items/[1-3]"}, ids[:3]},
+		{"status", models.RequestsFilter{StatusCode: 404}, []string{ids[0], ids[2], ids[4], ids[6], ids[8]}},
+		{"content type", models.RequestsFilter{ContentType: "text/html"}, ids},
+		{"time", models.RequestsFilter{From: baseTime.Add(2 * time.Minute), To: baseTime.Add(4 * time.Minute)},
+			ids[1:4]},
+		{"combined", models.RequestsFilter{Method: "GET", StatusCode: 404, PathGlob: "/api/*/[5-9]"},
+			[]string{ids[4], ids[6], ids[8]}},
+	}
+
+	for _, test := range tests {
+		test.filter.SortBy = models.SortByID
+		test.filter.Limit = 2
+
+		got := listAll(t, storage, test.filter)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: ids = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func testListSorting(t *testing.T, storage usecases.WebApiInterface) {
+	ids := populate(t, storage, 6)
+
+	reversed := slices.Clone(ids)
+	slices.Reverse(reversed)
+
+	tests := []struct {
+		sortBy string
+		desc   bool
+		want   []string
+	}{
+		{models.SortByID, true, reversed},
+		{models.SortByTime, false, ids},
+		{models.SortByTime, true, reversed},
+		{models.SortByDuration, false, reversed},
+		{models.SortBySize, false, ids},
+	}
+
+	for _, test := range tests {
+		got := listAll(t, storage, models.RequestsFilter{SortBy: test.sortBy, Desc: test.desc, Limit: 4})
+		if !slices.Equal(got, test.want) {
+			t.Errorf("sort %s desc=%v: ids = %v, want %v", test.sortBy, test.desc, got, test.want)
+		}
+	}
+
+	pending, err := storage.SaveRequest(newRequest(7))
+	if err != nil {
+		t.Fatalf("SaveRequest: %v", err)
+	}
+
+	for _, sortBy := range []string{models.SortByDuration, models.SortBySize} {
+		got := listAll(t, storage, models.RequestsFilter{SortBy: sortBy, Desc: true, Limit: 4})
+		if len(got) != len(ids)+1 || got[len(got)-1] != pending {
+			t.Errorf("sort %s desc: ids = %v, want request %s without response last", sortBy, got, pending)
+		}
+	}
+}
+
+func testListChildren(t *testing.T, storage usecases.WebApiInterface) {
+	ids := populate(t, storage, 2)
+
+	children := make([]string, 0, 3)
+
+	for i, parentID := range []string{ids[0], ids[1], ids[0]} {
+		request := newRequest(3 + i)
+		request.ParentID = parentID
+
+		id, err := storage.SaveRequest(request)
+		if err != nil {
+			t.Fatalf("SaveRequest: %v", err)
+		}
+
+		children = append(children, id)
+	}
+
+	got := listAll(t, storage, models.RequestsFilter{ParentID: ids[0], SortBy: models.SortByID, Limit: 1})
+	if want := []string{children[0], children[2]}; !slices.Equal(got, want) {
+		t.Errorf("children of %s = %v, want %v", ids[0], got, want)
+	}
+
+	err := storage.DeleteRequest(children[0])
+	if err != nil {
+		t.Fatalf("DeleteRequest: %v", err)
+	}
+
+	got = listAll(t, storage, models.RequestsFilter{ParentID: ids[0], SortBy: models.SortByID})
+	if want := []string{children[2]}; !slices.Equal(got, want) {
+		t.Errorf("children after delete = %v, want %v", got, want)
+	}
+}
+
+func testSearch(t *testing.T, storage usecases.WebApiInterface) {
+	ids := populate(t, storage, 5)
+
+	tests := []struct {
+		name  string
+		query models.SearchQuery
+		want  []string
+	}{
+		{"literal", models.SearchQuery{Query: "secret0014", Mode: models.SearchModeLiteral}, []string{ids[1]}},
+		{"case sensitive", models.SearchQuery{Query: "SECRET0014", Mode: models.SearchModeLiteral}, []string{}},
+		{"ignore case", models.SearchQuery{Query: "SECRET0014", Mode: models.SearchModeLiteral, IgnoreCase: true},
+			[]string{ids[1]}},
+		{"regex", models.SearchQuery{Query: `secret00(07|21)`, Mode: models.SearchModeRegex},
+			[]string{ids[2], ids[0]}},
+		{"request header", models.SearchQuery{Query: "request-4", Fields: []string{models.FieldRequestHeaders}},
+			[]string{ids[3]}},
+		{"request body", models.SearchQuery{Query: "body of request 5"}, []string{ids[4]}},
+	}
+
+	for _, test := range tests {
+		test.query.Limit = models.DefaultSearchLimit
+
+		hits, err := storage.Search(&test.query)
+		if err != nil {
+			t.Fatalf("%s: Search: %v", test.name, err)
+		}
+
+		got := make([]string, 0, len(hits))
+		for _, hit := range hits {
+			got = append(got, hit.ID)
+
+			for _, match := range hit.Matches {
+				if match.HighlightStart >= match.HighlightEnd || match.HighlightEnd > len(match.Snippet) {
+					t.Errorf("%s: bad highlight %+v", test.name, match)
+				}
+			}
+		}
+
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: ids = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func testSearchLargeBody(t *testing.T, storage usecases.WebApiInterface) {
+	ids := populate(t, storage, 2)
+
+	response := newResponse(1)
+	response.Body = append(bytes.Repeat([]byte("filler "), 300_000), []byte(" needle9token")...)
+
+	err := storage.SaveResponse(response, ids[0])
+	if err != nil {
+		t.Fatalf("SaveResponse: %v", err)
+	}
+
+	hits, err := storage.Search(&models.SearchQuery{Query: "needle9token", Mode: models.SearchModeLiteral, Limit: 10})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if len(hits) != 1 || hits[0].ID != ids[0] {
+		t.Errorf("match past the indexed part of a body: %d hits, want request %s", len(hits), ids[0])
+	}
+}
+
+func testDeleteRequest(t *testing.T, storage usecases.WebApiInterface) {
+	ids := populate(t, storage, 5)
+
+	err := storage.DeleteRequest(ids[2])
+	if err != nil {
+		t.Fatalf("DeleteRequest: %v", err)
+	}
+
+	if !errors.Is(storage.DeleteRequest(ids[2]), models.ErrNotFound) {
+		t.Errorf("second DeleteRequest did not return ErrNotFound")
+	}
+
+	if _, err = storage.GetRequest(ids[2]); err == nil {
+		t.Errorf("GetRequest returned deleted request")
+	}
+
+	want := []string{ids[0], ids[1], ids[3], ids[4]}
+
+	requests, err := storage.GetAllRequests()
+	if err != nil {
+		t.Fatalf("GetAllRequests: %v", err)
+	}
+
+	got := make([]string, 0, len(requests))
+	for _, req := range requests {
+		got = append(got, req.ID)
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("GetAllRequests ids = %v, want %v", got, want)
+	}
+
+	for _, sortBy := range []string{models.SortByID, models.SortByTime, models.SortBySize} {
+		got = listAll(t, storage, models.RequestsFilter{SortBy: sortBy, Limit: 2})
+		if !slices.Equal(got, want) {
+			t.Errorf("sort %s: ids = %v, want %v", sortBy, got, want)
+		}
+	}
+
+	got = listAll(t, storage, models.RequestsFilter{SortBy: models.SortByID, StatusCode: 404, Limit: 2})
+	if !slices.Equal(got, []string{ids[0], ids[4]}) {
+		t.Errorf("filtered ids = %v, want %v", got, []string{ids[0], ids[4]})
+	}
+
+	hits, err := storage.Search(&models.SearchQuery{Query: "secret0021", Limit: models.DefaultSearchLimit})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if len(hits) != 0 {
+		t.Errorf("Search returned deleted request %s", hits[0].ID)
+	}
+
+	id, err := storage.SaveRequest(newRequest(6))
+	if err != nil {
+		t.Fatalf("SaveRequest: %v", err)
+	}
+
+	if slices.Contains(ids, id) {
+		t.Errorf("SaveRequest reused id %s", id)
+	}
+}
+
+func testDeleteAllRequests(t *testing.T, storage usecases.WebApiInterface) {
+	populate(t, storage, 4)
+
+	deleted, err := storage.DeleteAllRequests()
+	if err != nil {
+		t.Fatalf("DeleteAllRequests: %v", err)
+	}
+
+	if deleted != 4 {
+		t.Errorf("DeleteAllRequests deleted %d requests, want 4", deleted)
+	}
+
+	requests, err := storage.GetAllRequests()
+	if err != nil {
+		t.Fatalf("GetAllRequests: %v", err)
+	}
+
+	if len(requests) != 0 {
+		t.Errorf("GetAllRequests returned %d requests after deleting history", len(requests))
+	}
+
+	if got := listAll(t, storage, models.RequestsFilter{Limit: 2}); len(got) != 0 {
+		t.Errorf("ListRequests returned %v after deleting history", got)
+	}
+
+	ids := populate(t, storage, 2)
+	if got := listAll(t, storage, models.RequestsFilter{Limit: 2}); !slices.Equal(got, ids) {
+		t.Errorf("ListRequests ids = %v, want %v", got, ids)
+	}
+}
+
+func testRetentionPolicy(t *testing.T, storage usecases.WebApiInterface) {
+	policy, err := storage.GetRetentionPolicy()
+	if err != nil {
+		t.Fatalf("GetRetentionPolicy: %v", err)
+	}
+
+	if !policy.IsEmpty() {
+		t.Errorf("default retention policy = %+v, want empty", policy)
+	}
+
+	want := &models.RetentionPolicy{
+		RetentionRule: models.RetentionRule{MaxEntries: 10, MaxAge: models.Duration(time.Hour)},
+		Hosts:         map[string]models.RetentionRule{"example.com": {MaxBodyBytes: 1024}},
+	}
+
+	err = storage.SaveRetentionPolicy(want)
+	if err != nil {
+		t.Fatalf("SaveRetentionPolicy: %v", err)
+	}
+
+	policy, err = storage.GetRetentionPolicy()
+	if err != nil {
+		t.Fatalf("GetRetentionPolicy: %v", err)
+	}
+
+	if policy.MaxEntries != want.MaxEntries || policy.MaxAge != want.MaxAge ||
+		policy.Hosts["example.com"] != want.Hosts["example.com"] {
+		t.Errorf("GetRetentionPolicy = %+v, want %+v", policy, want)
+	}
+}
+
+func testScope(t *testing.T, storage usecases.WebApiInterface) {
+	scope, err := storage.GetScope()
+	if err != nil {
+		t.Fatalf("GetScope: %v", err)
+	}
+
+	if !scope.IsEmpty() {
+		t.Errorf("default scope = %+v, want empty", scope)
+	}
+
+	want := &models.Scope{
+		Include:    []*models.ScopeRule{{Host: "*.example.com", Scheme: "https"}},
+		Exclude:    []*models.ScopeRule{{Path: "^/logout"}},
+		OutOfScope: models.OutOfScopeBlock,
+	}
+
+	err = storage.SaveScope(want)
+	if err != nil {
+		t.Fatalf("SaveScope: %v", err)
+	}
+
+	scope, err = storage.GetScope()
+	if err != nil {
+		t.Fatalf("GetScope: %v", err)
+	}
+
+	if len(scope.Include) != 1 || *scope.Include[0] != *want.Include[0] || len(scope.Exclude) != 1 ||
+		scope.Exclude[0].Path != want.Exclude[0].Path || scope.OutOfScope != want.OutOfScope {
+		t.Errorf("GetScope = %+v, want %+v", scope, want)
+	}
+}
+
+func newIssue(issueType, severity, path string, seen time.Time) *models.Issue {
+	return &models.Issue{
+		Type:      issueType,
+		Severity:  severity,
+		Host:      "example.com",
+		Path:      path,
+		Evidence:  seen.String(),
+		FirstSeen: seen,
+		LastSeen:  seen,
+	}
+}
+
+func testIssues(t *testing.T, storage usecases.WebApiInterface) {
+	_, err := storage.GetIssue("1")
+	if !errors.Is(err, models.ErrIssueNotFound) {
+		t.Errorf("GetIssue on empty storage error = %v, want %v", err, models.ErrIssueNotFound)
+	}
+
+	first := newIssue("missing_csp", models.SeverityLow, "/", baseTime)
+
+	err = storage.SaveIssue(first)
+	if err != nil {
+		t.Fatalf("SaveIssue: %v", err)
+	}
+
+	if first.ID == "" || first.Count != 1 {
+		t.Errorf("saved issue = %+v, want id and count 1", first)
+	}
+
+	repeated := newIssue("missing_csp", models.SeverityLow, "/", baseTime.Add(time.Minute))
+
+	err = storage.SaveIssue(repeated)
+	if err != nil {
+		t.Fatalf("SaveIssue: %v", err)
+	}
+
+	if repeated.ID != first.ID || repeated.Count != 2 || !repeated.FirstSeen.Equal(baseTime) ||
+		repeated.Evidence != baseTime.Add(time.Minute).String() {
+		t.Errorf("merged issue = %+v, want id %s, count 2 and latest evidence", repeated, first.ID)
+	}
+
+	err = storage.SaveIssue(newIssue("stack_trace", models.SeverityMedium, "/error", baseTime))
+	if err != nil {
+		t.Fatalf("SaveIssue: %v", err)
+	}
+
+	issues, err := storage.ListIssues(&models.IssuesFilter{})
+	if err != nil {
+		t.Fatalf("ListIssues: %v", err)
+	}
+
+	if len(issues) != 2 || issues[0].Type != "stack_trace" || issues[1].Count != 2 {
+		t.Errorf("ListIssues = %+v, want stack_trace first and merged missing_csp", issues)
+	}
+
+	issues, err = storage.ListIssues(&models.IssuesFilter{Severity: models.SeverityMedium})
+	if err != nil {
+		t.Fatalf("ListIssues: %v", err)
+	}
+
+	if len(issues) != 1 || issues[0].Type != "stack_trace" {
+		t.Errorf("ListIssues by severity = %+v, want only stack_trace", issues)
+	}
+
+	issue, err := storage.GetIssue(first.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+
+	if issue.Count != 2 {
+		t.Errorf("GetIssue count = %d, want 2", issue.Count)
+	}
+}
+
+func testRestoreTransaction(t *testing.T, storage usecases.WebApiInterface) {
+	request := newRequest(3)
+	request.ID = "10"
+
+	err := storage.RestoreTransaction(request, newResponse(3))
+	if err != nil {
+		t.Fatalf("RestoreTransaction: %v", err)
+	}
+
+	restored := newRequest(1)
+	restored.ID = "4"
+
+	err = storage.RestoreTransaction(restored, nil)
+	if err != nil {
+		t.Fatalf("RestoreTransaction: %v", err)
+	}
+
+	req, err := storage.GetRequest("10")
+	if err != nil || req.Path != request.Path {
+		t.Fatalf("GetRequest(10) = %+v, %v", req, err)
+	}
+
+	resp, err := storage.GetResponse("10")
+	if err != nil || string(resp.Body) != string(newResponse(3).Body) {
+		t.Fatalf("GetResponse(10) = %+v, %v", resp, err)
+	}
+
+	got := listAll(t, storage, models.RequestsFilter{SortBy: models.SortByID, Limit: 5})
+	if !slices.Equal(got, []string{"4", "10"}) {
+		t.Errorf("ListRequests ids = %v, want [4 10]", got)
+	}
+
+	id, err := storage.SaveRequest(newRequest(5))
+	if err != nil {
+		t.Fatalf("SaveRequest: %v", err)
+	}
+
+	if id != "11" {
+		t.Errorf("SaveRequest after restore returned id %s, want 11", id)
+	}
+}
@@ -0,0 +1,66 @@
+package search
+
+import (
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"slices"
+	"strconv"
+)
+
+type Loader func(id string) (*network.HTTPRequest, *network.HTTPResponse, error)
+
+func SortNewestFirst(ids []string) {
+	slices.SortFunc(ids, func(a, b string) int {
+		aInt, _ := strconv.Atoi(a)
+		bInt, _ := strconv.Atoi(b)
+
+		return bInt - aInt
+	})
+}
+
+// Union adds the ids of more that are not in ids yet.
+func Union(ids, more []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		seen[id] = struct{}{}
+	}
+
+	for _, id := range more {
+		if _, ok := seen[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func Collect(ids []string, matcher *Matcher, limit int, load Loader) []*models.SearchHit {
+	hits := make([]*models.SearchHit, 0)
+
+	for _, id := range ids {
+		if len(hits) >= limit {
+			break
+		}
+
+		req, resp, err := load(id)
+		if err != nil {
+			continue
+		}
+
+		fields := make([]Field, 0, 4)
+		if req != nil {
+			fields = append(fields, RequestFields(req)...)
+		}
+
+		if resp != nil {
+			fields = append(fields, ResponseFields(resp)...)
+		}
+
+		matches := matcher.Match(fields)
+		if len(matches) > 0 {
+			hits = append(hits, &models.SearchHit{ID: id, Matches: matches})
+		}
+	}
+
+	return hits
+}
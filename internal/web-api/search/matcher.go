@@ -0,0 +1,79 @@
+package search
+
+import (
+	"proxy/internal/web-api/models"
+	"regexp"
+	"unicode/utf8"
+)
+
+const (
+	snippetContext     = 40
+	maxMatchesPerField = 5
+)
+
+type Matcher struct {
+	re     *regexp.Regexp
+	fields []string
+}
+
+func NewMatcher(query *models.SearchQuery) (*Matcher, error) {
+	if query.Query == "" {
+		return nil, models.ErrBadSearchQuery
+	}
+
+	expr := query.Query
+	if query.Mode != models.SearchModeRegex {
+		expr = regexp.QuoteMeta(expr)
+	}
+
+	if query.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, models.ErrBadSearchQuery
+	}
+
+	return &Matcher{re: re, fields: query.Fields}, nil
+}
+
+func (matcher *Matcher) Match(fields []Field) []*models.SearchMatch {
+	matches := make([]*models.SearchMatch, 0)
+
+	for _, field := range fields {
+		if !hasField(matcher.fields, field.Name) {
+			continue
+		}
+
+		for _, loc := range matcher.re.FindAllIndex(field.Content, maxMatchesPerField) {
+			if loc[0] == loc[1] {
+				continue
+			}
+
+			matches = append(matches, snippet(field, loc[0], loc[1]))
+		}
+	}
+
+	return matches
+}
+
+func snippet(field Field, start, end int) *models.SearchMatch {
+	from := max(start-snippetContext, 0)
+	for from > 0 && !utf8.RuneStart(field.Content[from]) {
+		from--
+	}
+
+	to := min(end+snippetContext, len(field.Content))
+	for to < len(field.Content) && !utf8.RuneStart(field.Content[to]) {
+		to++
+	}
+
+	return &models.SearchMatch{
+		Field:          field.Name,
+		Offset:         start,
+		Snippet:        string(field.Content[from:to]),
+		HighlightStart: start - from,
+		HighlightEnd:   end - from,
+	}
+}
@@ -0,0 +1,149 @@
+package search
+
+import (
+	"bytes"
+	"net/http"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"regexp/syntax"
+	"strings"
+)
+
+const maxIndexedBytes = 1 << 20
+
+// Truncated is indexed for documents with a field longer than the indexed part, so that they
+// are always matched in full.
+const Truncated = "<truncated>"
+
+type Field struct {
+	Name    string
+	Content []byte
+}
+
+func RequestFields(req *network.HTTPRequest) []Field {
+	return []Field{
+		{Name: models.FieldRequestHeaders, Content: headerBytes(req.Header)},
+		{Name: models.FieldRequestBody, Content: req.DecodedBody()},
+	}
+}
+
+func ResponseFields(resp *network.HTTPResponse) []Field {
+	return []Field{
+		{Name: models.FieldResponseHeaders, Content: headerBytes(resp.Headers)},
+		{Name: models.FieldResponseBody, Content: resp.Body},
+	}
+}
+
+func headerBytes(header http.Header) []byte {
+	buf := &bytes.Buffer{}
+	header.Write(buf)
+
+	return buf.Bytes()
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9'
+}
+
+func lower(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+
+	return b
+}
+
+func addTrigrams(set map[string]struct{}, data []byte) {
+	if len(data) > maxIndexedBytes {
+		data = data[:maxIndexedBytes]
+	}
+
+	for i := 0; i+3 <= len(data); i++ {
+		a, b, c := lower(data[i]), lower(data[i+1]), lower(data[i+2])
+		if !isWordByte(a) || !isWordByte(b) || !isWordByte(c) {
+			continue
+		}
+
+		set[string([]byte{a, b, c})] = struct{}{}
+	}
+}
+
+func Trigrams(fields []Field) []string {
+	set := make(map[string]struct{})
+
+	for _, field := range fields {
+		addTrigrams(set, field.Content)
+
+		if len(field.Content) > maxIndexedBytes {
+			set[Truncated] = struct{}{}
+		}
+	}
+
+	return setToSlice(set)
+}
+
+func QueryTrigrams(query *models.SearchQuery) []string {
+	set := make(map[string]struct{})
+
+	if query.Mode != models.SearchModeRegex {
+		addTrigrams(set, []byte(query.Query))
+
+		return setToSlice(set)
+	}
+
+	re, err := syntax.Parse(query.Query, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+
+	for _, literal := range requiredLiterals(re.Simplify()) {
+		addTrigrams(set, []byte(literal))
+	}
+
+	return setToSlice(set)
+}
+
+func requiredLiterals(re *syntax.Regexp) []string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return []string{string(re.Rune)}
+	case syntax.OpCapture, syntax.OpPlus:
+		return requiredLiterals(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min > 0 {
+			return requiredLiterals(re.Sub[0])
+		}
+	case syntax.OpConcat:
+		literals := make([]string, 0, len(re.Sub))
+		for _, sub := range re.Sub {
+			literals = append(literals, requiredLiterals(sub)...)
+		}
+
+		return literals
+	}
+
+	return nil
+}
+
+func setToSlice(set map[string]struct{}) []string {
+	result := make([]string, 0, len(set))
+	for key := range set {
+		result = append(result, key)
+	}
+
+	return result
+}
+
+func hasField(fields []string, name string) bool {
+	if len(fields) == 0 {
+		return true
+	}
+
+	for _, field := range fields {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+
+	return false
+}
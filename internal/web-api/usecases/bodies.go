@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"proxy/internal/network"
+	"proxy/internal/render"
+	"proxy/internal/web-api/models"
+)
+
+// MessageBody loads the body of the request or of the response with the given id, with its
+// content codings removed. A form body is written back from its parameters.
+func MessageBody(storage WebApiInterface, id, part string) (*render.Body, error) {
+	switch part {
+	case models.BodyPartRequest:
+		request, err := storage.GetRequest(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(request.PostParams) > 0 {
+			return &render.Body{Header: request.Header, Content: []byte(request.PostParams.Encode())}, nil
+		}
+
+		content, _ := network.DecodeBody(request.Header, request.Body)
+
+		return &render.Body{Header: request.Header, Content: content}, nil
+	case models.BodyPartResponse:
+		response, err := storage.GetResponse(id)
+		if err != nil {
+			return nil, err
+		}
+
+		return &render.Body{Header: response.ClientHeaders(), Content: response.Body}, nil
+	default:
+		return nil, models.ErrBadBodyPart
+	}
+}
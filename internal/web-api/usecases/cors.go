@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"proxy/internal/network"
+	"proxy/internal/scan"
+	"proxy/internal/web-api/models"
+	"strings"
+	"time"
+)
+
+func (scanner *Scanner) StartCORS(requestID string, config *models.CORSConfig) (*models.Scan, error) {
+	project, storage := scanner.projects.Active()
+
+	source, err := storage.GetRequest(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := url.Parse(source.URL())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrBadScan, err)
+	}
+
+	scope, err := scanner.scope(storage, source)
+	if err != nil {
+		return nil, err
+	}
+
+	probes := scan.CORSProbes(target, config.Origins)
+
+	for _, probe := range probes {
+		origins, err := processPayloads([]string{probe.Origin}, config.Processors)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", models.ErrBadScan, err)
+		}
+
+		probe.Origin = origins[0]
+	}
+
+	run, ctx := scanner.register(models.ScanCORS, requestID, len(probes), project, storage, scope)
+	run.scan.CORS = config
+
+	go func() {
+		stopped := runPool(ctx, len(probes), 1, 0, func(i int) {
+			run.probed(scanner.probeCORS(run, source, probes[i]))
+		})
+
+		run.finish(stopped)
+	}()
+
+	return run.snapshot(), nil
+}
+
+func (scanner *Scanner) probeCORS(run *scanRun, source *network.HTTPRequest, probe *scan.OriginProbe) *models.ProbeResult {
+	result := &models.ProbeResult{Name: probe.Name, Param: "Origin", Payload: probe.Origin}
+
+	request := source.Clone()
+	request.Header.Set("Origin", probe.Origin)
+
+	transaction, err := Repeat(run.storage, scanner.sender, request, source.ID)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	response := transaction.Response
+	result.RequestID = transaction.Request.ID
+	result.Status = response.Code
+
+	result.Severity, result.Finding = scan.CheckCORS(probe, response)
+	if result.Severity == "" {
+		return result
+	}
+
+	result.IssueID = reportIssue(run.storage, &models.Issue{
+		Type:      scan.IssueCORS,
+		Severity:  result.Severity,
+		Title:     "CORS policy trusts untrusted origins",
+		Detail:    result.Finding,
+		Host:      strings.ToLower(source.Host),
+		Path:      source.Path,
+		Param:     probe.Name,
+		Evidence:  scan.CORSEvidence(response),
+		RequestID: result.RequestID,
+	})
+
+	return result
+}
+
+// reportIssue saves an issue found by an active check and returns its id, or an empty id
+// when it could not be saved.
+func reportIssue(storage WebApiInterface, issue *models.Issue) string {
+	issue.Count = 1
+	issue.FirstSeen = time.Now()
+	issue.LastSeen = issue.FirstSeen
+
+	err := storage.SaveIssue(issue)
+	if err != nil {
+		log.Println("error saving issue", err)
+
+		return ""
+	}
+
+	return issue.ID
+}
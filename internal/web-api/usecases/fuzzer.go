@@ -0,0 +1,607 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"proxy/internal/fuzz"
+	"proxy/internal/insertion"
+	"proxy/internal/network"
+	"proxy/internal/oob"
+	"proxy/internal/web-api/models"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Fuzzer struct {
+	mu       sync.Mutex
+	projects *Projects
+	sender   Sender
+	listener *oob.Listener
+	lastID   int
+	attacks  map[string]*attackRun
+}
+
+type attackRun struct {
+	mu       sync.Mutex
+	attack   *models.Attack
+	cancel   context.CancelFunc
+	done     chan struct{}
+	storage  WebApiInterface
+	template *fuzz.Template
+	source   *network.HTTPRequest
+	points   []*insertion.Point
+	plan     *fuzz.Plan
+	scheme   string
+	scope    *models.Scope
+}
+
+func NewFuzzer(projects *Projects, sender Sender, listener *oob.Listener) *Fuzzer {
+	fuzzer := &Fuzzer{
+		projects: projects,
+		sender:   sender,
+		listener: listener,
+		attacks:  make(map[string]*attackRun),
+	}
+
+	projects.AddJobs(fuzzer)
+
+	return fuzzer
+}
+
+func (fuzzer *Fuzzer) Start(config *models.AttackConfig) (*models.Attack, error) {
+	if config.Concurrency == 0 {
+		config.Concurrency = models.DefaultAttackConcurrency
+	}
+
+	if config.Concurrency < 0 || config.Concurrency > models.MaxAttackConcurrency || config.Delay < 0 {
+		return nil, models.ErrBadAttack
+	}
+
+	project, storage := fuzzer.projects.Active()
+
+	run := &attackRun{storage: generatedStorage(storage)}
+
+	var (
+		defaults []string
+		err      error
+	)
+
+	if len(config.Points) > 0 {
+		defaults, err = run.usePoints(storage, config)
+	} else {
+		run.template, run.scheme, err = attackTemplate(storage, config.RequestID, config.Template, config.Scheme)
+		if err == nil {
+			defaults = run.template.Defaults()
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	run.scope, err = storage.GetScope()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := run.build(defaults, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrBadAttack, err)
+	}
+
+	if !run.scope.Allows(target) {
+		return nil, models.ErrOutOfScope
+	}
+
+	sets := make([][]string, 0, len(config.Payloads))
+
+	for _, source := range config.Payloads {
+		set, err := fuzz.Expand(source, capturedValues(storage))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", models.ErrBadAttack, err)
+		}
+
+		set, err = processPayloads(set, config.Processors)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", models.ErrBadAttack, err)
+		}
+
+		sets = append(sets, set)
+	}
+
+	run.plan, err = fuzz.NewPlan(config.Mode, defaults, sets)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrBadAttack, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	run.cancel = cancel
+	run.done = make(chan struct{})
+
+	fuzzer.mu.Lock()
+	fuzzer.lastID++
+	run.attack = &models.Attack{
+		ID:        strconv.Itoa(fuzzer.lastID),
+		Project:   project,
+		Config:    config,
+		Status:    models.JobRunning,
+		Total:     run.plan.Len(),
+		StartedAt: time.Now(),
+		Results:   make([]*models.AttackResult, 0, run.plan.Len()),
+	}
+	fuzzer.attacks[run.attack.ID] = run
+	fuzzer.mu.Unlock()
+
+	go fuzzer.run(ctx, run)
+
+	return run.snapshot(false), nil
+}
+
+func attackTemplate(storage WebApiInterface, requestID, text, scheme string) (*fuzz.Template, string, error) {
+	if requestID != "" {
+		request, err := storage.GetRequest(requestID)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if text == "" {
+			text = string(request.Raw())
+		}
+
+		if scheme == "" {
+			scheme = request.Scheme
+		}
+	}
+
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	template, err := fuzz.ParseTemplate(text)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", models.ErrBadAttack, err)
+	}
+
+	return template, scheme, nil
+}
+
+// usePoints targets insertion points of the stored request instead of marked template positions,
+// so that values inside JSON, multipart and XML bodies are inserted with the body re-encoded.
+func (run *attackRun) usePoints(storage WebApiInterface, config *models.AttackConfig) ([]string, error) {
+	if config.RequestID == "" || config.Template != "" {
+		return nil, fmt.Errorf("%w: points need a request_id and no template", models.ErrBadAttack)
+	}
+
+	source, err := storage.GetRequest(config.RequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	run.source = source
+	run.scheme = source.Scheme
+	defaults := make([]string, 0, len(config.Points))
+
+	for _, name := range config.Points {
+		point, err := insertion.ParsePoint(name)
+		if err == nil {
+			point, err = insertion.Find(source, point)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", models.ErrBadAttack, name, err)
+		}
+
+		run.points = append(run.points, point)
+		defaults = append(defaults, point.Value)
+	}
+
+	return defaults, nil
+}
+
+func (run *attackRun) hasPlaceholder(values []string) bool {
+	if run.template != nil {
+		return oob.HasPlaceholder(run.template.Render(values))
+	}
+
+	return slices.ContainsFunc(values, oob.HasPlaceholder)
+}
+
+// build makes the request for one set of position values, with out-of-band placeholders
+// replaced by token when it is not nil.
+func (run *attackRun) build(values []string, token *models.InteractionToken) (*network.HTTPRequest, error) {
+	if run.template != nil {
+		raw := run.template.Render(values)
+		if token != nil {
+			raw = oob.Substitute(raw, token)
+		}
+
+		return network.ParseRawRequest([]byte(raw), run.scheme)
+	}
+
+	request := run.source
+
+	for i, point := range run.points {
+		value := values[i]
+		if token != nil {
+			value = oob.Substitute(value, token)
+		}
+
+		var err error
+
+		request, err = insertion.Inject(request, point, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return request, nil
+}
+
+func (fuzzer *Fuzzer) Preview(preview *models.PayloadPreviewRequest) (*models.PayloadPreview, error) {
+	steps, err := fuzz.ProcessSteps(preview.Payload, preview.Processors)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrBadAttack, err)
+	}
+
+	result := &models.PayloadPreview{
+		Payload:   preview.Payload,
+		Processed: preview.Payload,
+		Steps:     steps,
+	}
+
+	if len(steps) > 0 {
+		result.Processed = steps[len(steps)-1]
+	}
+
+	if preview.RequestID == "" && preview.Template == "" {
+		return result, nil
+	}
+
+	if preview.Point != "" {
+		return fuzzer.previewPoint(preview, result)
+	}
+
+	template, _, err := attackTemplate(fuzzer.projects.Storage(), preview.RequestID, preview.Template, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if preview.Position < 0 || preview.Position >= template.Positions() {
+		return nil, fmt.Errorf("%w: position %d out of range", models.ErrBadAttack, preview.Position)
+	}
+
+	values := slices.Clone(template.Defaults())
+	values[preview.Position] = result.Processed
+	result.Context = template.Render(values)
+
+	return result, nil
+}
+
+// previewPoint shows the processed payload injected at an insertion point of the source request.
+func (fuzzer *Fuzzer) previewPoint(preview *models.PayloadPreviewRequest,
+	result *models.PayloadPreview) (*models.PayloadPreview, error) {
+	if preview.RequestID == "" {
+		return nil, fmt.Errorf("%w: point preview needs a request", models.ErrBadAttack)
+	}
+
+	source, err := fuzzer.projects.GetRequest(preview.RequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	point, err := insertion.ParsePoint(preview.Point)
+	if err == nil {
+		point, err = insertion.Find(source, point)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", models.ErrBadAttack, preview.Point, err)
+	}
+
+	injected, err := insertion.Inject(source, point, result.Processed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrBadAttack, err)
+	}
+
+	result.Context = string(injected.Raw())
+
+	return result, nil
+}
+
+func (fuzzer *Fuzzer) run(ctx context.Context, run *attackRun) {
+	config := run.attack.Config
+
+	stopped := runPool(ctx, run.plan.Len(), config.Concurrency, time.Duration(config.Delay), func(i int) {
+		run.add(fuzzer.execute(run, i))
+	})
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	run.attack.Status = models.JobFinished
+	if stopped {
+		run.attack.Status = models.JobStopped
+	}
+
+	run.attack.FinishedAt = time.Now()
+	close(run.done)
+}
+
+func (fuzzer *Fuzzer) execute(run *attackRun, i int) *models.AttackResult {
+	config := run.attack.Config
+	values, position, payloads := run.plan.At(i)
+
+	result := &models.AttackResult{
+		Index:    i,
+		Position: position,
+		Payloads: payloads,
+	}
+
+	// Every request carrying an out-of-band placeholder gets its own token, so that a callback
+	// points at the exact payload that caused it.
+	var token *models.InteractionToken
+
+	if run.hasPlaceholder(values) {
+		token = fuzzer.listener.Token(&models.InteractionToken{
+			Origin:   models.OriginFuzz,
+			JobID:    run.attack.ID,
+			SourceID: config.RequestID,
+		})
+
+		result.Token = token.Token
+
+		for i, payload := range result.Payloads {
+			result.Payloads[i] = oob.Substitute(payload, token)
+		}
+	}
+
+	request, err := run.build(values, token)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	if !run.scope.Allows(request) {
+		result.Error = models.ErrOutOfScope.Error()
+
+		return result
+	}
+
+	var response *network.HTTPResponse
+
+	if config.Store {
+		var transaction *models.Transaction
+
+		transaction, err = Repeat(run.storage, fuzzer.sender, request, config.RequestID)
+		if err == nil {
+			response = transaction.Response
+			result.RequestID = transaction.Request.ID
+		}
+	} else {
+		response, err = Exchange(fuzzer.sender, request)
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	if token != nil {
+		fuzzer.listener.Attach(token.Token, strings.Join(result.Payloads, ", "), result.RequestID)
+	}
+
+	result.Status = response.Code
+	result.Length = len(response.Body)
+	result.Time = response.Duration
+
+	if len(config.Grep) > 0 {
+		result.Grep = grep(response, config.Grep)
+	}
+
+	return result
+}
+
+func grep(response *network.HTTPResponse, patterns []string) map[string]bool {
+	builder := &strings.Builder{}
+
+	for name, values := range response.Headers {
+		builder.WriteString(name + ": " + strings.Join(values, ", ") + "\n")
+	}
+
+	builder.Write(response.Body)
+
+	text := strings.ToLower(builder.String())
+	matches := make(map[string]bool, len(patterns))
+
+	for _, pattern := range patterns {
+		matches[pattern] = strings.Contains(text, strings.ToLower(pattern))
+	}
+
+	return matches
+}
+
+func capturedValues(storage WebApiInterface) fuzz.Capture {
+	return func(field, name string) ([]string, error) {
+		requests, err := storage.GetAllRequests()
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]string, 0)
+		seen := make(map[string]bool)
+
+		for _, request := range requests {
+			for _, value := range fieldValues(request, field, name) {
+				if !seen[value] {
+					seen[value] = true
+					values = append(values, value)
+				}
+			}
+		}
+
+		return values, nil
+	}
+}
+
+func fieldValues(request *network.HTTPRequest, field, name string) []string {
+	switch field {
+	case models.PayloadFieldQuery:
+		return request.GetParams[name]
+	case models.PayloadFieldBody:
+		return request.PostParams[name]
+	case models.PayloadFieldHeader:
+		return request.Header.Values(name)
+	case models.PayloadFieldCookie:
+		values := make([]string, 0)
+
+		for _, cookie := range request.Cookies {
+			if cookie.Name == name {
+				values = append(values, cookie.Value)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
+
+func (run *attackRun) add(result *models.AttackResult) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	run.attack.Results = append(run.attack.Results, result)
+	run.attack.Done++
+}
+
+func (run *attackRun) snapshot(withResults bool) *models.Attack {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	attack := *run.attack
+	attack.Results = nil
+
+	if withResults {
+		attack.Results = slices.Clone(run.attack.Results)
+		slices.SortFunc(attack.Results, func(a, b *models.AttackResult) int {
+			return a.Index - b.Index
+		})
+	}
+
+	return &attack
+}
+
+func (fuzzer *Fuzzer) find(id string) (*attackRun, error) {
+	fuzzer.mu.Lock()
+	defer fuzzer.mu.Unlock()
+
+	run, ok := fuzzer.attacks[id]
+	if !ok || run.attack.Project != fuzzer.projects.Current() {
+		return nil, models.ErrAttackNotFound
+	}
+
+	return run, nil
+}
+
+// List returns the attacks of the current project.
+func (fuzzer *Fuzzer) List() []*models.Attack {
+	project := fuzzer.projects.Current()
+
+	fuzzer.mu.Lock()
+	runs := make([]*attackRun, 0, len(fuzzer.attacks))
+
+	for _, run := range fuzzer.attacks {
+		if run.attack.Project == project {
+			runs = append(runs, run)
+		}
+	}
+	fuzzer.mu.Unlock()
+
+	attacks := make([]*models.Attack, 0, len(runs))
+	for _, run := range runs {
+		attacks = append(attacks, run.snapshot(false))
+	}
+
+	slices.SortFunc(attacks, func(a, b *models.Attack) int {
+		return compareNumericIDs(a.ID, b.ID)
+	})
+
+	return attacks
+}
+
+func (fuzzer *Fuzzer) Get(id string) (*models.Attack, error) {
+	run, err := fuzzer.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	attack := run.snapshot(true)
+
+	for i, result := range attack.Results {
+		if result.Token == "" {
+			continue
+		}
+
+		copied := *result
+		copied.Interactions = len(fuzzer.listener.Interactions(result.Token))
+		attack.Results[i] = &copied
+	}
+
+	return attack, nil
+}
+
+func (fuzzer *Fuzzer) Stop(id string) error {
+	run, err := fuzzer.find(id)
+	if err != nil {
+		return err
+	}
+
+	run.cancel()
+
+	return nil
+}
+
+func (fuzzer *Fuzzer) Delete(id string) error {
+	err := fuzzer.Stop(id)
+	if err != nil {
+		return err
+	}
+
+	fuzzer.mu.Lock()
+	delete(fuzzer.attacks, id)
+	fuzzer.mu.Unlock()
+
+	return nil
+}
+
+// DropProject stops the attacks of a deleted project, waits for them to finish writing and
+// forgets them.
+func (fuzzer *Fuzzer) DropProject(project string) {
+	fuzzer.mu.Lock()
+	runs := make([]*attackRun, 0)
+
+	for id, run := range fuzzer.attacks {
+		if run.attack.Project == project {
+			runs = append(runs, run)
+			delete(fuzzer.attacks, id)
+		}
+	}
+	fuzzer.mu.Unlock()
+
+	for _, run := range runs {
+		run.cancel()
+		<-run.done
+	}
+}
+
+func compareNumericIDs(a, b string) int {
+	aInt, _ := strconv.Atoi(a)
+	bInt, _ := strconv.Atoi(b)
+
+	return aInt - bInt
+}
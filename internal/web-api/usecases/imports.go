@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+)
+
+func Import(storage WebApiInterface, entries []*models.ImportEntry) *models.ImportResult {
+	result := &models.ImportResult{
+		Entries: make([]*models.ImportedEntry, 0, len(entries)),
+	}
+
+	for index, entry := range entries {
+		imported := &models.ImportedEntry{Index: index}
+
+		err := entry.Err
+		if err == nil {
+			imported.ID, err = SaveTransaction(storage, entry.Transaction.Request, entry.Transaction.Response)
+		}
+
+		if err != nil {
+			imported.Error = err.Error()
+			result.Failed++
+		} else {
+			result.Imported++
+		}
+
+		result.Entries = append(result.Entries, imported)
+	}
+
+	return result
+}
+
+func SaveTransaction(storage WebApiInterface, request *network.HTTPRequest,
+	response *network.HTTPResponse) (string, error) {
+	id, err := storage.SaveRequest(request)
+	if err != nil {
+		return "", err
+	}
+
+	if response == nil {
+		return id, nil
+	}
+
+	err = storage.SaveResponse(response, id)
+	if err != nil {
+		return id, err
+	}
+
+	return id, nil
+}
@@ -1,6 +1,9 @@
 package usecases
 
-import "proxy/internal/network"
+import (
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+)
 
 type WebApiInterface interface {
 	SaveRequest(request *network.HTTPRequest) (string, error)
@@ -8,4 +11,30 @@ type WebApiInterface interface {
 	GetRequest(id string) (*network.HTTPRequest, error)
 	GetResponse(id string) (*network.HTTPResponse, error)
 	GetAllRequests() ([]*network.HTTPRequest, error)
+	ListRequests(filter *models.RequestsFilter) (*models.RequestsPage, error)
+	Search(query *models.SearchQuery) ([]*models.SearchHit, error)
+	DeleteRequest(id string) error
+	DeleteAllRequests() (int, error)
+	GetRetentionPolicy() (*models.RetentionPolicy, error)
+	SaveRetentionPolicy(policy *models.RetentionPolicy) error
+	GetScope() (*models.Scope, error)
+	SaveScope(scope *models.Scope) error
+	SaveIssue(issue *models.Issue) error
+	GetIssue(id string) (*models.Issue, error)
+	ListIssues(filter *models.IssuesFilter) ([]*models.Issue, error)
+	RestoreTransaction(request *network.HTTPRequest, response *network.HTTPResponse) error
+}
+
+// StorageSource hands out the storage of the current project.
+type StorageSource interface {
+	Storage() WebApiInterface
+}
+
+type ProjectsRepository interface {
+	OpenProject(name string) (WebApiInterface, error)
+	ListProjects() ([]*models.Project, error)
+	SaveProject(project *models.Project) error
+	DeleteProject(name string) error
+	GetCurrentProject() (string, error)
+	SaveCurrentProject(name string) error
 }
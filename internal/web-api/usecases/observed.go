@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"proxy/internal/network"
+	"slices"
+)
+
+// HistoryListener is told about changes to project histories. generated is set for
+// transactions sent by attacks and scans rather than passed through the proxy or repeated by hand.
+type HistoryListener interface {
+	TransactionSaved(project string, storage WebApiInterface, request *network.HTTPRequest,
+		response *network.HTTPResponse, generated bool)
+	HistoryChanged(project string)
+}
+
+// ProjectJobs runs background jobs that write to a project and must end before it is deleted.
+type ProjectJobs interface {
+	DropProject(project string)
+}
+
+// observedStorage tells the project listeners about every change made through a project storage.
+type observedStorage struct {
+	WebApiInterface
+	project   string
+	projects  *Projects
+	generated bool
+}
+
+// generatedStorage returns a view of storage whose saved transactions are reported as generated.
+func generatedStorage(storage WebApiInterface) WebApiInterface {
+	observed, ok := storage.(*observedStorage)
+	if !ok {
+		return storage
+	}
+
+	generated := *observed
+	generated.generated = true
+
+	return &generated
+}
+
+func (storage *observedStorage) SaveResponse(response *network.HTTPResponse, id string) error {
+	err := storage.WebApiInterface.SaveResponse(response, id)
+	if err != nil {
+		return err
+	}
+
+	request, err := storage.GetRequest(id)
+	if err == nil {
+		storage.projects.transactionSaved(storage.project, storage.WebApiInterface, request, response,
+			storage.generated)
+	}
+
+	return nil
+}
+
+func (storage *observedStorage) RestoreTransaction(request *network.HTTPRequest,
+	response *network.HTTPResponse) error {
+	err := storage.WebApiInterface.RestoreTransaction(request, response)
+	if err != nil {
+		return err
+	}
+
+	if response != nil {
+		storage.projects.transactionSaved(storage.project, storage.WebApiInterface, request, response,
+			storage.generated)
+	}
+
+	return nil
+}
+
+func (storage *observedStorage) DeleteRequest(id string) error {
+	err := storage.WebApiInterface.DeleteRequest(id)
+	if err != nil {
+		return err
+	}
+
+	storage.projects.historyChanged(storage.project)
+
+	return nil
+}
+
+func (storage *observedStorage) DeleteAllRequests() (int, error) {
+	deleted, err := storage.WebApiInterface.DeleteAllRequests()
+	if err != nil {
+		return deleted, err
+	}
+
+	storage.projects.historyChanged(storage.project)
+
+	return deleted, nil
+}
+
+func (projects *Projects) Subscribe(listener HistoryListener) {
+	projects.mu.Lock()
+	defer projects.mu.Unlock()
+
+	projects.listeners = append(projects.listeners, listener)
+}
+
+func (projects *Projects) AddJobs(jobs ProjectJobs) {
+	projects.mu.Lock()
+	defer projects.mu.Unlock()
+
+	projects.jobs = append(projects.jobs, jobs)
+}
+
+func (projects *Projects) dropJobs(project string) {
+	projects.mu.RLock()
+	jobs := slices.Clone(projects.jobs)
+	projects.mu.RUnlock()
+
+	for _, runner := range jobs {
+		runner.DropProject(project)
+	}
+}
+
+func (projects *Projects) subscribers() []HistoryListener {
+	projects.mu.RLock()
+	defer projects.mu.RUnlock()
+
+	return projects.listeners
+}
+
+func (projects *Projects) transactionSaved(project string, storage WebApiInterface,
+	request *network.HTTPRequest, response *network.HTTPResponse, generated bool) {
+	for _, listener := range projects.subscribers() {
+		listener.TransactionSaved(project, storage, request, response, generated)
+	}
+}
+
+func (projects *Projects) historyChanged(project string) {
+	for _, listener := range projects.subscribers() {
+		listener.HistoryChanged(project)
+	}
+}
+
+func (projects *Projects) open(name string) (WebApiInterface, error) {
+	storage, err := projects.repo.OpenProject(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &observedStorage{WebApiInterface: storage, project: name, projects: projects}, nil
+}
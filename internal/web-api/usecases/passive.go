@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"proxy/internal/network"
+	"proxy/internal/passive"
+	"sync"
+	"time"
+)
+
+const (
+	passiveQueueSize = 1000
+
+	// passiveDropLogInterval limits how often skipped transactions are logged.
+	passiveDropLogInterval = 10 * time.Second
+)
+
+type passiveJob struct {
+	storage  WebApiInterface
+	request  *network.HTTPRequest
+	response *network.HTTPResponse
+}
+
+// PassiveScanner analyzes saved transactions in the background and stores the issues in the
+// project the transaction belongs to. Transactions generated by attacks and scans are not
+// analyzed, and when the scanner falls behind new transactions are skipped.
+type PassiveScanner struct {
+	analyzer *passive.Analyzer
+	queue    chan *passiveJob
+
+	mu        sync.Mutex
+	dropped   int
+	droppedAt time.Time
+}
+
+func NewPassiveScanner(projects *Projects, analyzer *passive.Analyzer) *PassiveScanner {
+	scanner := &PassiveScanner{
+		analyzer: analyzer,
+		queue:    make(chan *passiveJob, passiveQueueSize),
+	}
+
+	projects.Subscribe(scanner)
+
+	return scanner
+}
+
+func (scanner *PassiveScanner) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-scanner.queue:
+			for _, issue := range scanner.analyzer.Analyze(job.request, job.response) {
+				err := job.storage.SaveIssue(issue)
+				if err != nil {
+					log.Println("error saving issue", err)
+				}
+			}
+		}
+	}
+}
+
+func (scanner *PassiveScanner) TransactionSaved(project string, storage WebApiInterface,
+	request *network.HTTPRequest, response *network.HTTPResponse, generated bool) {
+	if generated {
+		return
+	}
+
+	select {
+	case scanner.queue <- &passiveJob{storage: storage, request: request, response: response}:
+	default:
+		scanner.drop()
+	}
+}
+
+func (scanner *PassiveScanner) drop() {
+	scanner.mu.Lock()
+	defer scanner.mu.Unlock()
+
+	scanner.dropped++
+
+	if time.Since(scanner.droppedAt) < passiveDropLogInterval {
+		return
+	}
+
+	log.Println("passive scan queue is full, skipped requests:", scanner.dropped)
+
+	scanner.dropped = 0
+	scanner.droppedAt = time.Now()
+}
+
+func (scanner *PassiveScanner) HistoryChanged(project string) {}
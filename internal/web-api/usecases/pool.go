@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// runPool calls work for every index in [0, count) on concurrency workers, starting at most
+// one call per delay. It reports whether ctx was cancelled before all work was handed out.
+func runPool(ctx context.Context, count, concurrency int, delay time.Duration, work func(i int)) bool {
+	jobs := make(chan int)
+
+	wg := &sync.WaitGroup{}
+
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				work(i)
+			}
+		}()
+	}
+
+	var throttle <-chan time.Time
+
+	if delay > 0 {
+		ticker := time.NewTicker(delay)
+		defer ticker.Stop()
+
+		throttle = ticker.C
+	}
+
+	stopped := false
+
+	for i := 0; i < count && !stopped; i++ {
+		if throttle != nil && i > 0 {
+			select {
+			case <-throttle:
+			case <-ctx.Done():
+				stopped = true
+
+				continue
+			}
+		}
+
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			stopped = true
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return stopped
+}
@@ -0,0 +1,410 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type Projects struct {
+	mu        sync.RWMutex
+	repo      ProjectsRepository
+	name      string
+	current   WebApiInterface
+	listeners []HistoryListener
+	jobs      []ProjectJobs
+}
+
+func NewProjects(repo ProjectsRepository) (*Projects, error) {
+	projects := &Projects{repo: repo}
+
+	list, err := repo.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.ContainsFunc(list, func(project *models.Project) bool {
+		return project.Name == models.DefaultProject
+	}) {
+		err = repo.SaveProject(&models.Project{Name: models.DefaultProject, CreatedAt: time.Now()})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	name, err := repo.GetCurrentProject()
+	if err != nil {
+		return nil, err
+	}
+
+	err = projects.Switch(name)
+	if err != nil {
+		log.Println("error opening current project, falling back to default", err)
+
+		err = projects.Switch(models.DefaultProject)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return projects, nil
+}
+
+func (projects *Projects) find(name string) (*models.Project, error) {
+	list, err := projects.repo.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range list {
+		if project.Name == name {
+			return project, nil
+		}
+	}
+
+	return nil, models.ErrProjectNotFound
+}
+
+// Storage returns the storage of the current project. Writes that belong together go through one
+// Storage call, so that a project switch can not split them.
+func (projects *Projects) Storage() WebApiInterface {
+	projects.mu.RLock()
+	defer projects.mu.RUnlock()
+
+	return projects.current
+}
+
+// Active returns the name and storage of the current project, read together so that a job
+// started during a switch is labelled with the project it writes to.
+func (projects *Projects) Active() (string, WebApiInterface) {
+	projects.mu.RLock()
+	defer projects.mu.RUnlock()
+
+	return projects.name, projects.current
+}
+
+func (projects *Projects) Current() string {
+	projects.mu.RLock()
+	defer projects.mu.RUnlock()
+
+	return projects.name
+}
+
+func (projects *Projects) List() (*models.ProjectsList, error) {
+	list, err := projects.repo.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProjectsList{
+		Current:  projects.Current(),
+		Projects: list,
+	}, nil
+}
+
+func (projects *Projects) Create(project *models.Project) error {
+	if !models.IsValidProjectName(project.Name) {
+		return models.ErrBadProjectName
+	}
+
+	_, err := projects.find(project.Name)
+	if err == nil {
+		return models.ErrProjectExists
+	}
+
+	if !errors.Is(err, models.ErrProjectNotFound) {
+		return err
+	}
+
+	project.CreatedAt = time.Now()
+
+	return projects.repo.SaveProject(project)
+}
+
+func (projects *Projects) Switch(name string) error {
+	_, err := projects.find(name)
+	if err != nil {
+		return err
+	}
+
+	storage, err := projects.open(name)
+	if err != nil {
+		return err
+	}
+
+	err = projects.repo.SaveCurrentProject(name)
+	if err != nil {
+		return err
+	}
+
+	projects.mu.Lock()
+	projects.name = name
+	projects.current = storage
+	projects.mu.Unlock()
+
+	return nil
+}
+
+func (projects *Projects) Delete(name string) error {
+	if name == models.DefaultProject || name == projects.Current() {
+		return models.ErrProjectProtected
+	}
+
+	_, err := projects.find(name)
+	if err != nil {
+		return err
+	}
+
+	projects.dropJobs(name)
+
+	err = projects.repo.DeleteProject(name)
+	if err != nil {
+		return err
+	}
+
+	projects.historyChanged(name)
+
+	return nil
+}
+
+func (projects *Projects) Each(fn func(name string, storage WebApiInterface) error) error {
+	list, err := projects.repo.ListProjects()
+	if err != nil {
+		return err
+	}
+
+	for _, project := range list {
+		storage, err := projects.open(project.Name)
+		if err != nil {
+			return err
+		}
+
+		err = fn(project.Name, storage)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (projects *Projects) Archive(name string) (*models.ProjectArchive, error) {
+	project, err := projects.find(name)
+	if err != nil {
+		return nil, err
+	}
+
+	storage, err := projects.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	retention, err := storage.GetRetentionPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := storage.GetScope()
+	if err != nil {
+		return nil, err
+	}
+
+	if scope.IsEmpty() {
+		scope = nil
+	}
+
+	requests, err := storage.GetAllRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := storage.ListIssues(&models.IssuesFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &models.ProjectArchive{
+		Version:      models.ProjectArchiveVersion,
+		Project:      project,
+		Retention:    retention,
+		Scope:        scope,
+		Transactions: make([]*models.Transaction, 0, len(requests)),
+		Issues:       issues,
+	}
+
+	for _, request := range requests {
+		transaction := &models.Transaction{Request: request}
+
+		response, err := storage.GetResponse(request.ID)
+		if err == nil {
+			transaction.Response = response
+		}
+
+		archive.Transactions = append(archive.Transactions, transaction)
+	}
+
+	return archive, nil
+}
+
+func (projects *Projects) Restore(archive *models.ProjectArchive, name string) (*models.Project, error) {
+	if archive.Version != models.ProjectArchiveVersion || archive.Project == nil {
+		return nil, models.ErrBadArchive
+	}
+
+	if name == "" {
+		name = archive.Project.Name
+	}
+
+	project := &models.Project{
+		Name:        name,
+		Description: archive.Project.Description,
+	}
+
+	err := projects.Create(project)
+	if err != nil {
+		return nil, err
+	}
+
+	err = projects.restore(archive, name)
+	if err != nil {
+		deleteErr := projects.repo.DeleteProject(name)
+		if deleteErr != nil {
+			log.Println("error cleaning up partially restored project", deleteErr)
+		}
+
+		return nil, err
+	}
+
+	return project, nil
+}
+
+func (projects *Projects) restore(archive *models.ProjectArchive, name string) error {
+	storage, err := projects.open(name)
+	if err != nil {
+		return err
+	}
+
+	if archive.Retention != nil {
+		err = storage.SaveRetentionPolicy(archive.Retention)
+		if err != nil {
+			return err
+		}
+	}
+
+	if archive.Scope != nil {
+		err = archive.Scope.Validate()
+		if err != nil {
+			return fmt.Errorf("%w: %w", models.ErrBadArchive, err)
+		}
+
+		err = storage.SaveScope(archive.Scope)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, transaction := range archive.Transactions {
+		if transaction.Request == nil || transaction.Request.ID == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(transaction.Request.ID)
+		if err != nil || id <= 0 || strconv.Itoa(id) != transaction.Request.ID {
+			return fmt.Errorf("%w: request id %q", models.ErrBadArchive, transaction.Request.ID)
+		}
+
+		err = storage.RestoreTransaction(transaction.Request, transaction.Response)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, issue := range archive.Issues {
+		if issue == nil {
+			continue
+		}
+
+		issue.ID = ""
+
+		err = storage.SaveIssue(issue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (projects *Projects) SaveRequest(request *network.HTTPRequest) (string, error) {
+	return projects.Storage().SaveRequest(request)
+}
+
+func (projects *Projects) SaveResponse(response *network.HTTPResponse, id string) error {
+	return projects.Storage().SaveResponse(response, id)
+}
+
+func (projects *Projects) GetRequest(id string) (*network.HTTPRequest, error) {
+	return projects.Storage().GetRequest(id)
+}
+
+func (projects *Projects) GetResponse(id string) (*network.HTTPResponse, error) {
+	return projects.Storage().GetResponse(id)
+}
+
+func (projects *Projects) GetAllRequests() ([]*network.HTTPRequest, error) {
+	return projects.Storage().GetAllRequests()
+}
+
+func (projects *Projects) ListRequests(filter *models.RequestsFilter) (*models.RequestsPage, error) {
+	return projects.Storage().ListRequests(filter)
+}
+
+func (projects *Projects) Search(query *models.SearchQuery) ([]*models.SearchHit, error) {
+	return projects.Storage().Search(query)
+}
+
+func (projects *Projects) DeleteRequest(id string) error {
+	return projects.Storage().DeleteRequest(id)
+}
+
+func (projects *Projects) DeleteAllRequests() (int, error) {
+	return projects.Storage().DeleteAllRequests()
+}
+
+func (projects *Projects) GetRetentionPolicy() (*models.RetentionPolicy, error) {
+	return projects.Storage().GetRetentionPolicy()
+}
+
+func (projects *Projects) SaveRetentionPolicy(policy *models.RetentionPolicy) error {
+	return projects.Storage().SaveRetentionPolicy(policy)
+}
+
+func (projects *Projects) GetScope() (*models.Scope, error) {
+	return projects.Storage().GetScope()
+}
+
+func (projects *Projects) SaveScope(scope *models.Scope) error {
+	return projects.Storage().SaveScope(scope)
+}
+
+func (projects *Projects) SaveIssue(issue *models.Issue) error {
+	return projects.Storage().SaveIssue(issue)
+}
+
+func (projects *Projects) GetIssue(id string) (*models.Issue, error) {
+	return projects.Storage().GetIssue(id)
+}
+
+func (projects *Projects) ListIssues(filter *models.IssuesFilter) ([]*models.Issue, error) {
+	return projects.Storage().ListIssues(filter)
+}
+
+func (projects *Projects) RestoreTransaction(request *network.HTTPRequest, response *network.HTTPResponse) error {
+	return projects.Storage().RestoreTransaction(request, response)
+}
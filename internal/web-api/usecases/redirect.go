@@ -0,0 +1,199 @@
+package usecases
+
+import (
+	"fmt"
+	"proxy/internal/fuzz"
+	"proxy/internal/insertion"
+	"proxy/internal/network"
+	"proxy/internal/scan"
+	"proxy/internal/web-api/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+type redirectProbe struct {
+	param   *insertion.Point
+	kind    string
+	payload *scan.RedirectPayload
+	value   string
+	token   string
+}
+
+func (scanner *Scanner) StartRedirect(requestID string, config *models.RedirectConfig) (*models.Scan, error) {
+	if config.Wait < 0 {
+		return nil, models.ErrBadScan
+	}
+
+	if config.Wait == 0 {
+		config.Wait = models.DefaultCallbackWait
+	}
+
+	project, storage := scanner.projects.Active()
+
+	source, err := storage.GetRequest(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := scanner.scope(storage, source)
+	if err != nil {
+		return nil, err
+	}
+
+	probes := make([]*redirectProbe, 0)
+
+	for _, param := range scan.URLParams(source) {
+		if len(config.Params) > 0 && !slices.Contains(config.Params, param.Name) &&
+			!slices.Contains(config.Params, param.String()) {
+			continue
+		}
+
+		for _, kind := range scan.RedirectKinds {
+			probes = append(probes, &redirectProbe{param: param, kind: kind})
+		}
+	}
+
+	if len(probes) == 0 {
+		return nil, fmt.Errorf("%w: request has no URL parameters", models.ErrBadScan)
+	}
+
+	if _, err := fuzz.Process("", config.Processors); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrBadScan, err)
+	}
+
+	run, ctx := scanner.register(models.ScanRedirect, requestID, len(probes), project, storage, scope)
+	run.scan.Redirect = config
+
+	for _, probe := range probes {
+		token := scanner.listener.Token(&models.InteractionToken{
+			Origin:   models.OriginScan,
+			JobID:    run.scan.ID,
+			SourceID: requestID,
+			Param:    probe.param.String(),
+		})
+
+		probe.token = token.Token
+		probe.payload = scan.NewRedirectPayload(probe.kind, token.URL)
+		probe.value, _ = fuzz.Process(probe.payload.Value, config.Processors)
+	}
+
+	go func() {
+		results := make([]*models.ProbeResult, len(probes))
+
+		stopped := runPool(ctx, len(probes), 1, 0, func(i int) {
+			results[i] = scanner.probeRedirect(run, source, probes[i])
+			run.probed(results[i])
+		})
+
+		if !stopped {
+			select {
+			case <-ctx.Done():
+				stopped = true
+			case <-time.After(time.Duration(config.Wait)):
+			}
+		}
+
+		scanner.collectCallbacks(run, source, probes, results)
+		run.finish(stopped)
+	}()
+
+	return run.snapshot(), nil
+}
+
+func (scanner *Scanner) probeRedirect(run *scanRun, source *network.HTTPRequest,
+	probe *redirectProbe) *models.ProbeResult {
+	result := &models.ProbeResult{Name: probe.payload.Name, Param: probe.param.String(), Payload: probe.value}
+
+	request, err := insertion.Inject(source, probe.param, probe.value)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	if !run.scope.Allows(request) {
+		result.Error = models.ErrOutOfScope.Error()
+
+		return result
+	}
+
+	transaction, err := Repeat(run.storage, scanner.sender, request, source.ID)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	scanner.listener.Attach(probe.token, probe.value, transaction.Request.ID)
+
+	result.RequestID = transaction.Request.ID
+	result.Status = transaction.Response.Code
+
+	target, ok := scan.RedirectTarget(transaction.Response, probe.payload.Value)
+	if !ok {
+		return result
+	}
+
+	result.Severity = models.SeverityMedium
+	result.Finding = "redirects to " + target
+	result.IssueID = reportIssue(run.storage, &models.Issue{
+		Type:      scan.IssueOpenRedirect,
+		Severity:  result.Severity,
+		Title:     "Parameter controls where the response redirects",
+		Detail:    result.Finding,
+		Host:      strings.ToLower(source.Host),
+		Path:      source.Path,
+		Param:     result.Param,
+		Evidence:  target,
+		RequestID: result.RequestID,
+	})
+
+	return result
+}
+
+// collectCallbacks reports parameters whose callback URL was fetched by the server.
+func (scanner *Scanner) collectCallbacks(run *scanRun, source *network.HTTPRequest, probes []*redirectProbe,
+	results []*models.ProbeResult) {
+	for i, probe := range probes {
+		if results[i] == nil || results[i].RequestID == "" {
+			continue
+		}
+
+		interactions := scanner.listener.Interactions(probe.token)
+		if len(interactions) == 0 {
+			continue
+		}
+
+		finding := fmt.Sprintf("%s callback received from %s", interactions[0].Protocol, interactions[0].Source)
+
+		issueID := reportIssue(run.storage, &models.Issue{
+			Type:      scan.IssueSSRF,
+			Severity:  models.SeverityHigh,
+			Title:     "Server fetches URLs taken from a parameter",
+			Detail:    finding,
+			Host:      strings.ToLower(source.Host),
+			Path:      source.Path,
+			Param:     probe.param.String(),
+			Evidence:  evidence(interactions[0].Raw),
+			RequestID: results[i].RequestID,
+		})
+
+		run.mu.Lock()
+		results[i].Severity = models.SeverityHigh
+		results[i].Finding = strings.TrimPrefix(results[i].Finding+"; "+finding, "; ")
+		results[i].IssueID = issueID
+		run.mu.Unlock()
+	}
+}
+
+func evidence(raw string) string {
+	const maxEvidence = 200
+
+	line, _, _ := strings.Cut(raw, "\n")
+	if len(line) > maxEvidence {
+		return line[:maxEvidence]
+	}
+
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"net/http"
+	"proxy/internal/network"
+	"proxy/internal/web-api/models"
+	"time"
+)
+
+type Sender interface {
+	Send(request *network.HTTPRequest) (*http.Response, error)
+}
+
+func Exchange(sender Sender, request *network.HTTPRequest) (*network.HTTPResponse, error) {
+	start := time.Now()
+
+	resp, err := sender.Send(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	response, err := network.NewHTTPResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Duration = time.Since(start)
+
+	return response, nil
+}
+
+func Repeat(storage WebApiInterface, sender Sender, request *network.HTTPRequest,
+	parentID string) (*models.Transaction, error) {
+	request.ID = ""
+	request.ParentID = parentID
+	request.Time = time.Now()
+
+	response, err := Exchange(sender, request)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = SaveTransaction(storage, request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Transaction{Request: request, Response: response}, nil
+}
@@ -0,0 +1,152 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"proxy/internal/web-api/models"
+	"time"
+)
+
+type Pruner struct {
+	projects *Projects
+	interval time.Duration
+}
+
+type retentionUsage struct {
+	entries int
+	bytes   int64
+}
+
+func NewPruner(projects *Projects, interval time.Duration) *Pruner {
+	return &Pruner{
+		projects: projects,
+		interval: interval,
+	}
+}
+
+func (pruner *Pruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(pruner.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := pruner.Prune()
+			if err != nil {
+				log.Println("error pruning history", err)
+
+				continue
+			}
+
+			if deleted > 0 {
+				log.Println("pruned requests from history:", deleted)
+			}
+		}
+	}
+}
+
+func (pruner *Pruner) Prune() (int, error) {
+	total := 0
+
+	err := pruner.projects.Each(func(name string, storage WebApiInterface) error {
+		deleted, err := prune(storage)
+		total += deleted
+
+		return err
+	})
+
+	return total, err
+}
+
+func prune(storage WebApiInterface) (int, error) {
+	policy, err := storage.GetRetentionPolicy()
+	if err != nil {
+		return 0, err
+	}
+
+	if policy.IsEmpty() {
+		return 0, nil
+	}
+
+	ids, err := expired(storage, policy, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return deleteIDs(storage, ids)
+}
+
+func expired(storage WebApiInterface, policy *models.RetentionPolicy, now time.Time) ([]string, error) {
+	filter := &models.RequestsFilter{
+		SortBy: models.SortByTime,
+		Desc:   true,
+		Limit:  models.MaxPageLimit,
+	}
+
+	usage := make(map[string]*retentionUsage)
+	ids := make([]string, 0)
+
+	for {
+		page, err := storage.ListRequests(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, summary := range page.Requests {
+			scope, rule := policy.RuleFor(summary.Host)
+
+			scopeUsage, ok := usage[scope]
+			if !ok {
+				scopeUsage = &retentionUsage{}
+				usage[scope] = scopeUsage
+			}
+
+			scopeUsage.entries++
+			scopeUsage.bytes += int64(summary.RequestSize + summary.Size)
+
+			switch {
+			case rule.MaxAge > 0 && now.Sub(summary.Time) > time.Duration(rule.MaxAge),
+				rule.MaxEntries > 0 && scopeUsage.entries > rule.MaxEntries,
+				rule.MaxBodyBytes > 0 && scopeUsage.bytes > rule.MaxBodyBytes:
+				ids = append(ids, summary.ID)
+			}
+		}
+
+		if page.NextCursor == "" {
+			return ids, nil
+		}
+
+		filter.Cursor = page.NextCursor
+	}
+}
+
+func DeleteRequests(storage WebApiInterface, filter *models.RequestsFilter) (int, error) {
+	ids, err := CollectIDs(storage, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return deleteIDs(storage, ids)
+}
+
+func deleteIDs(storage WebApiInterface, ids []string) (int, error) {
+	deleted := 0
+
+	for _, id := range ids {
+		err := storage.DeleteRequest(id)
+		if errors.Is(err, models.ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return deleted, err
+		}
+
+		deleted++
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"proxy/internal/diff"
+	"proxy/internal/web-api/models"
+	"sort"
+)
+
+func RevisionTree(storage WebApiInterface, id string) (*models.RevisionNode, error) {
+	root, err := storage.GetRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{root.ID: true}
+
+	for root.ParentID != "" && !seen[root.ParentID] {
+		parent, err := storage.GetRequest(root.ParentID)
+		if err != nil {
+			break
+		}
+
+		seen[parent.ID] = true
+		root = parent
+	}
+
+	summary := models.NewRequestSummary(root)
+
+	response, err := storage.GetResponse(root.ID)
+	if err == nil {
+		summary.SetResponse(response)
+	}
+
+	node := &models.RevisionNode{RequestSummary: summary}
+
+	err = addRevisions(storage, node, map[string]bool{root.ID: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+func addRevisions(storage WebApiInterface, node *models.RevisionNode, visited map[string]bool) error {
+	children, err := CollectSummaries(storage, &models.RequestsFilter{
+		ParentID: node.ID,
+		SortBy:   models.SortByID,
+	})
+	if err != nil {
+		return err
+	}
+
+	node.Children = make([]*models.RevisionNode, 0, len(children))
+
+	for _, child := range children {
+		if visited[child.ID] {
+			continue
+		}
+
+		visited[child.ID] = true
+
+		childNode := &models.RevisionNode{RequestSummary: child}
+
+		err = addRevisions(storage, childNode, visited)
+		if err != nil {
+			return err
+		}
+
+		node.Children = append(node.Children, childNode)
+	}
+
+	return nil
+}
+
+func Diff(storage WebApiInterface, left, right, part string) (*models.DiffResult, error) {
+	var (
+		leftHead, rightHead []string
+		leftBody, rightBody []byte
+		err                 error
+	)
+
+	switch part {
+	case models.DiffPartRequest:
+		leftHead, leftBody, err = requestDiffSide(storage, left)
+		if err != nil {
+			return nil, err
+		}
+
+		rightHead, rightBody, err = requestDiffSide(storage, right)
+	case models.DiffPartResponse:
+		leftHead, leftBody, err = responseDiffSide(storage, left)
+		if err != nil {
+			return nil, err
+		}
+
+		rightHead, rightBody, err = responseDiffSide(storage, right)
+	default:
+		return nil, models.ErrBadDiffPart
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.DiffResult{
+		Left:    left,
+		Right:   right,
+		Part:    part,
+		Headers: diff.Lines(leftHead, rightHead),
+	}
+
+	leftJSON, leftOK := diff.ParseJSON(leftBody)
+	rightJSON, rightOK := diff.ParseJSON(rightBody)
+
+	if leftOK && rightOK {
+		result.JSON = diff.JSON(leftJSON, rightJSON)
+
+		leftBody, _ = json.MarshalIndent(leftJSON, "", "  ")
+		rightBody, _ = json.MarshalIndent(rightJSON, "", "  ")
+	}
+
+	result.Body = diff.Lines(diff.SplitLines(string(leftBody)), diff.SplitLines(string(rightBody)))
+
+	return result, nil
+}
+
+func requestDiffSide(storage WebApiInterface, id string) ([]string, []byte, error) {
+	request, err := storage.GetRequest(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	head := append([]string{fmt.Sprintf("%s %s %s", request.Method, request.URL(), request.Proto)},
+		headerLines(request.Header)...)
+
+	return head, request.DecodedBody(), nil
+}
+
+func responseDiffSide(storage WebApiInterface, id string) ([]string, []byte, error) {
+	response, err := storage.GetResponse(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	head := append([]string{fmt.Sprintf("%s %s", response.Proto, response.Message)}, headerLines(response.Headers)...)
+
+	return head, response.Body, nil
+}
+
+func headerLines(header http.Header) []string {
+	lines := make([]string, 0, len(header))
+
+	for name, values := range header {
+		for _, value := range values {
+			lines = append(lines, name+": "+value)
+		}
+	}
+
+	sort.Strings(lines)
+
+	return lines
+}
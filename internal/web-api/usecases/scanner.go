@@ -0,0 +1,467 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"proxy/internal/fuzz"
+	"proxy/internal/network"
+	"proxy/internal/oob"
+	"proxy/internal/scan"
+	"proxy/internal/web-api/models"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type Scanner struct {
+	mu       sync.Mutex
+	projects *Projects
+	sender   Sender
+	listener *oob.Listener
+	lastID   int
+	scans    map[string]*scanRun
+}
+
+type scanRun struct {
+	mu      sync.Mutex
+	scan    *models.Scan
+	cancel  context.CancelFunc
+	done    chan struct{}
+	storage WebApiInterface
+	scope   *models.Scope
+}
+
+func NewScanner(projects *Projects, sender Sender, listener *oob.Listener) *Scanner {
+	scanner := &Scanner{
+		projects: projects,
+		sender:   sender,
+		listener: listener,
+		scans:    make(map[string]*scanRun),
+	}
+
+	projects.AddJobs(scanner)
+
+	return scanner
+}
+
+func (scanner *Scanner) register(scanType, requestID string, total int, project string,
+	storage WebApiInterface, scope *models.Scope) (*scanRun, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	scanner.mu.Lock()
+	defer scanner.mu.Unlock()
+
+	scanner.lastID++
+
+	run := &scanRun{
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		storage: generatedStorage(storage),
+		scope:   scope,
+		scan: &models.Scan{
+			ID:        strconv.Itoa(scanner.lastID),
+			Project:   project,
+			Type:      scanType,
+			RequestID: requestID,
+			Status:    models.JobRunning,
+			Total:     total,
+			StartedAt: time.Now(),
+		},
+	}
+
+	scanner.scans[run.scan.ID] = run
+
+	return run, ctx
+}
+
+func (scanner *Scanner) StartDiscovery(requestID string, config *models.DiscoveryConfig) (*models.Scan, error) {
+	if config.Concurrency == 0 {
+		config.Concurrency = models.DefaultScanConcurrency
+	}
+
+	if config.Concurrency < 0 || config.Concurrency > models.MaxScanConcurrency || config.Delay < 0 {
+		return nil, models.ErrBadScan
+	}
+
+	project, storage := scanner.projects.Active()
+
+	source, err := storage.GetRequest(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	words := slices.Clone(config.Words)
+
+	if config.Wordlist != "" {
+		wordlist, err := fuzz.ReadWordlist(config.Wordlist)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", models.ErrBadScan, err)
+		}
+
+		words = append(words, wordlist...)
+	}
+
+	candidates := scan.Candidates(words, config.Extensions)
+	if len(candidates) == 0 || len(candidates) > fuzz.MaxRequests {
+		return nil, fmt.Errorf("%w: wordlist must contain between 1 and %d paths", models.ErrBadScan, fuzz.MaxRequests)
+	}
+
+	candidates, err = processPayloads(candidates, config.Processors)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrBadScan, err)
+	}
+
+	basePath := scan.BasePath(config.BasePath)
+
+	scope, err := scanner.scope(storage, scan.Probe(source, basePath))
+	if err != nil {
+		return nil, err
+	}
+
+	run, ctx := scanner.register(models.ScanDiscovery, requestID, len(candidates), project, storage, scope)
+	run.scan.Discovery = config
+
+	go func() {
+		wildcards := scanner.wildcards(run, source, basePath, config.Extensions, config.Processors)
+
+		stopped := runPool(ctx, len(candidates), config.Concurrency, time.Duration(config.Delay), func(i int) {
+			scanner.discover(run, source, basePath+candidates[i], candidates[i], wildcards)
+		})
+
+		run.finish(stopped)
+	}()
+
+	return run.snapshot(), nil
+}
+
+// processPayloads returns a new slice with the processor chain applied to each payload.
+func processPayloads(payloads []string, processors []*models.PayloadProcessor) ([]string, error) {
+	processed := make([]string, len(payloads))
+
+	for i, payload := range payloads {
+		var err error
+
+		processed[i], err = fuzz.Process(payload, processors)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return processed, nil
+}
+
+// scope loads the project scope and checks that the scan target is inside it.
+func (scanner *Scanner) scope(storage WebApiInterface, target *network.HTTPRequest) (*models.Scope, error) {
+	scope, err := storage.GetScope()
+	if err != nil {
+		return nil, err
+	}
+
+	if !scope.Allows(target) {
+		return nil, models.ErrOutOfScope
+	}
+
+	return scope, nil
+}
+
+// wildcards requests random paths to learn how the server answers for paths that do not exist.
+func (scanner *Scanner) wildcards(run *scanRun, source *network.HTTPRequest, basePath string,
+	extensions []string, processors []*models.PayloadProcessor) []*scan.Fingerprint {
+	fingerprints := make([]*scan.Fingerprint, 0)
+
+	candidates, err := processPayloads(scan.Candidates([]string{scan.RandomToken(), scan.RandomToken()}, extensions),
+		processors)
+	if err != nil {
+		log.Println("error processing wildcard paths", err)
+
+		return fingerprints
+	}
+
+	for _, candidate := range candidates {
+		request := scan.Probe(source, basePath+candidate)
+		if !run.scope.Allows(request) {
+			continue
+		}
+
+		response, err := Exchange(scanner.sender, request)
+		if err != nil {
+			log.Println("error probing wildcard responses", err)
+
+			continue
+		}
+
+		if scan.Classify(response.Code) != "" {
+			fingerprints = append(fingerprints, scan.NewFingerprint(response, candidate))
+		}
+	}
+
+	return fingerprints
+}
+
+func (scanner *Scanner) discover(run *scanRun, source *network.HTTPRequest, path, candidate string,
+	wildcards []*scan.Fingerprint) {
+	request := scan.Probe(source, path)
+	request.Time = time.Now()
+
+	if !run.scope.Allows(request) {
+		run.progress(nil, false)
+
+		return
+	}
+
+	response, err := Exchange(scanner.sender, request)
+	if err != nil {
+		run.progress(nil, true)
+
+		return
+	}
+
+	class := scan.Classify(response.Code)
+	if class == "" {
+		run.progress(nil, false)
+
+		return
+	}
+
+	fingerprint := scan.NewFingerprint(response, candidate)
+
+	for _, wildcard := range wildcards {
+		if wildcard.Matches(fingerprint) {
+			run.progress(nil, false)
+
+			return
+		}
+	}
+
+	result := &models.DiscoveryResult{
+		Path:           path,
+		URL:            request.URL(),
+		Status:         response.Code,
+		Length:         len(response.Body),
+		RedirectTo:     response.Headers.Get("Location"),
+		Classification: class,
+	}
+
+	result.RequestID, err = SaveTransaction(run.storage, request, response)
+	if err != nil {
+		log.Println("error saving discovered endpoint", err)
+	}
+
+	run.progress(result, false)
+}
+
+func (run *scanRun) progress(found *models.DiscoveryResult, failed bool) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	run.scan.Done++
+
+	if failed {
+		run.scan.Errors++
+	}
+
+	if found != nil {
+		run.scan.Found = append(run.scan.Found, found)
+	}
+}
+
+func (run *scanRun) probed(result *models.ProbeResult) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	run.scan.Done++
+
+	if result.Error != "" {
+		run.scan.Errors++
+	}
+
+	run.scan.Probes = append(run.scan.Probes, result)
+}
+
+func (run *scanRun) finish(stopped bool) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	run.scan.Status = models.JobFinished
+	if stopped {
+		run.scan.Status = models.JobStopped
+	}
+
+	run.scan.FinishedAt = time.Now()
+	close(run.done)
+}
+
+func (run *scanRun) snapshot() *models.Scan {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	snapshot := *run.scan
+	snapshot.Found = slices.Clone(run.scan.Found)
+	snapshot.Probes = make([]*models.ProbeResult, len(run.scan.Probes))
+
+	for i, probe := range run.scan.Probes {
+		copied := *probe
+		snapshot.Probes[i] = &copied
+	}
+
+	slices.SortFunc(snapshot.Found, func(a, b *models.DiscoveryResult) int {
+		switch {
+		case a.Path < b.Path:
+			return -1
+		case a.Path > b.Path:
+			return 1
+		}
+
+		return 0
+	})
+
+	return &snapshot
+}
+
+func (scanner *Scanner) find(id string) (*scanRun, error) {
+	scanner.mu.Lock()
+	defer scanner.mu.Unlock()
+
+	run, ok := scanner.scans[id]
+	if !ok || run.scan.Project != scanner.projects.Current() {
+		return nil, models.ErrScanNotFound
+	}
+
+	return run, nil
+}
+
+// List returns the scans of the current project.
+func (scanner *Scanner) List() []*models.Scan {
+	project := scanner.projects.Current()
+
+	scanner.mu.Lock()
+	runs := make([]*scanRun, 0, len(scanner.scans))
+
+	for _, run := range scanner.scans {
+		if run.scan.Project == project {
+			runs = append(runs, run)
+		}
+	}
+	scanner.mu.Unlock()
+
+	scans := make([]*models.Scan, 0, len(runs))
+
+	for _, run := range runs {
+		snapshot := run.snapshot()
+		snapshot.Found = nil
+		snapshot.Probes = nil
+		scans = append(scans, snapshot)
+	}
+
+	slices.SortFunc(scans, func(a, b *models.Scan) int {
+		return compareNumericIDs(a.ID, b.ID)
+	})
+
+	return scans
+}
+
+// ProjectScans returns the scans of project with their results, for the project archive.
+func (scanner *Scanner) ProjectScans(project string) []*models.Scan {
+	scanner.mu.Lock()
+	runs := make([]*scanRun, 0, len(scanner.scans))
+
+	for _, run := range scanner.scans {
+		if run.scan.Project == project {
+			runs = append(runs, run)
+		}
+	}
+	scanner.mu.Unlock()
+
+	scans := make([]*models.Scan, 0, len(runs))
+	for _, run := range runs {
+		scans = append(scans, run.snapshot())
+	}
+
+	slices.SortFunc(scans, func(a, b *models.Scan) int {
+		return compareNumericIDs(a.ID, b.ID)
+	})
+
+	return scans
+}
+
+// Restore adds the scans of a restored project under new ids. Scans that were running when
+// the archive was made are kept as stopped.
+func (scanner *Scanner) Restore(project string, scans []*models.Scan) {
+	scanner.mu.Lock()
+	defer scanner.mu.Unlock()
+
+	for _, restored := range scans {
+		if restored == nil {
+			continue
+		}
+
+		scanner.lastID++
+
+		restored.ID = strconv.Itoa(scanner.lastID)
+		restored.Project = project
+
+		if restored.Status == models.JobRunning {
+			restored.Status = models.JobStopped
+		}
+
+		done := make(chan struct{})
+		close(done)
+
+		scanner.scans[restored.ID] = &scanRun{scan: restored, cancel: func() {}, done: done}
+	}
+}
+
+// DropProject stops the scans of a deleted project, waits for them to finish writing and
+// forgets them.
+func (scanner *Scanner) DropProject(project string) {
+	scanner.mu.Lock()
+	runs := make([]*scanRun, 0)
+
+	for id, run := range scanner.scans {
+		if run.scan.Project == project {
+			runs = append(runs, run)
+			delete(scanner.scans, id)
+		}
+	}
+	scanner.mu.Unlock()
+
+	for _, run := range runs {
+		run.cancel()
+		<-run.done
+	}
+}
+
+func (scanner *Scanner) Get(id string) (*models.Scan, error) {
+	run, err := scanner.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return run.snapshot(), nil
+}
+
+func (scanner *Scanner) Stop(id string) error {
+	run, err := scanner.find(id)
+	if err != nil {
+		return err
+	}
+
+	run.cancel()
+
+	return nil
+}
+
+func (scanner *Scanner) Delete(id string) error {
+	err := scanner.Stop(id)
+	if err != nil {
+		return err
+	}
+
+	scanner.mu.Lock()
+	delete(scanner.scans, id)
+	scanner.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"proxy/internal/network"
+	"proxy/internal/sitemap"
+	"proxy/internal/web-api/models"
+	"sync"
+)
+
+// SiteMap keeps a site map per project. A tree is built from the history on first use
+// and then updated as transactions are saved; deletions drop it so that it is rebuilt.
+type SiteMap struct {
+	mu       sync.Mutex
+	projects *Projects
+	trees    map[string]*sitemap.Tree
+}
+
+func NewSiteMap(projects *Projects) *SiteMap {
+	siteMap := &SiteMap{
+		projects: projects,
+		trees:    make(map[string]*sitemap.Tree),
+	}
+
+	projects.Subscribe(siteMap)
+
+	return siteMap
+}
+
+func (siteMap *SiteMap) Get(origin string) ([]*sitemap.Node, error) {
+	tree, err := siteMap.tree(siteMap.projects.Current(), siteMap.projects.Storage())
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.Snapshot(origin), nil
+}
+
+func (siteMap *SiteMap) tree(project string, storage WebApiInterface) (*sitemap.Tree, error) {
+	siteMap.mu.Lock()
+	tree, ok := siteMap.trees[project]
+	siteMap.mu.Unlock()
+
+	if ok {
+		return tree, nil
+	}
+
+	tree = sitemap.New()
+
+	siteMap.mu.Lock()
+	siteMap.trees[project] = tree
+	siteMap.mu.Unlock()
+
+	summaries, err := CollectSummaries(storage, &models.RequestsFilter{})
+	if err != nil {
+		siteMap.HistoryChanged(project)
+
+		return nil, err
+	}
+
+	for _, summary := range summaries {
+		request, err := storage.GetRequest(summary.ID)
+		if err != nil {
+			continue
+		}
+
+		response, err := storage.GetResponse(summary.ID)
+		if err != nil {
+			response = nil
+		}
+
+		tree.Add(request, response)
+	}
+
+	return tree, nil
+}
+
+func (siteMap *SiteMap) TransactionSaved(project string, storage WebApiInterface, request *network.HTTPRequest,
+	response *network.HTTPResponse, generated bool) {
+	siteMap.mu.Lock()
+	tree, ok := siteMap.trees[project]
+	siteMap.mu.Unlock()
+
+	if ok {
+		tree.Add(request, response)
+	}
+}
+
+func (siteMap *SiteMap) HistoryChanged(project string) {
+	siteMap.mu.Lock()
+	defer siteMap.mu.Unlock()
+
+	delete(siteMap.trees, project)
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"proxy/internal/web-api/models"
+)
+
+func CollectIDs(storage WebApiInterface, filter *models.RequestsFilter) ([]string, error) {
+	summaries, err := CollectSummaries(storage, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(summaries))
+	for i, summary := range summaries {
+		ids[i] = summary.ID
+	}
+
+	return ids, nil
+}
+
+func CollectSummaries(storage WebApiInterface, filter *models.RequestsFilter) ([]*models.RequestSummary, error) {
+	filter.Cursor = ""
+	filter.Limit = models.MaxPageLimit
+
+	summaries := make([]*models.RequestSummary, 0)
+
+	for {
+		page, err := storage.ListRequests(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		summaries = append(summaries, page.Requests...)
+
+		if page.NextCursor == "" {
+			return summaries, nil
+		}
+
+		filter.Cursor = page.NextCursor
+	}
+}
+
+func LoadTransactions(storage WebApiInterface, ids []string) ([]*models.Transaction, error) {
+	transactions := make([]*models.Transaction, 0, len(ids))
+
+	for _, id := range ids {
+		request, err := storage.GetRequest(id)
+		if err != nil {
+			return nil, err
+		}
+
+		transaction := &models.Transaction{Request: request}
+
+		response, err := storage.GetResponse(id)
+		if err == nil {
+			transaction.Response = response
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"fmt"
+	"proxy/internal/fuzz"
+	"proxy/internal/insertion"
+	"proxy/internal/network"
+	"proxy/internal/scan"
+	"proxy/internal/web-api/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+const xxeDoctypeParam = "doctype"
+
+func (scanner *Scanner) StartXXE(requestID string, config *models.XXEConfig) (*models.Scan, error) {
+	if config.Wait < 0 {
+		return nil, models.ErrBadScan
+	}
+
+	if config.Wait == 0 {
+		config.Wait = models.DefaultCallbackWait
+	}
+
+	project, storage := scanner.projects.Active()
+
+	source, err := storage.GetRequest(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !insertion.IsXML(source) {
+		return nil, fmt.Errorf("%w: request has no XML body", models.ErrBadScan)
+	}
+
+	doc, err := insertion.ParseXML(source.DecodedBody())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrBadScan, err)
+	}
+
+	scope, err := scanner.scope(storage, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline []byte
+
+	response, err := storage.GetResponse(requestID)
+	if err == nil {
+		baseline = response.Body
+	}
+
+	probes := make([]*scan.XXEProbe, 0)
+
+	for _, probe := range scan.XXEProbes(doc, scan.RandomToken(), "/nonexistent/"+scan.RandomToken()) {
+		if len(config.Params) == 0 || slices.Contains(config.Params, probe.Leaf.Path) {
+			probes = append(probes, probe)
+		}
+	}
+
+	if len(config.Params) > 0 && len(probes) == 0 {
+		return nil, fmt.Errorf("%w: no XML element matches params", models.ErrBadScan)
+	}
+
+	if _, err := fuzz.Process("", config.Processors); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrBadScan, err)
+	}
+
+	run, ctx := scanner.register(models.ScanXXE, requestID, len(probes)+1, project, storage, scope)
+	run.scan.XXE = config
+
+	token := scanner.listener.Token(&models.InteractionToken{
+		Origin:   models.OriginScan,
+		JobID:    run.scan.ID,
+		SourceID: requestID,
+		Param:    xxeDoctypeParam,
+	})
+
+	parameter := scan.XXEParameterProbe(doc, token.URL)
+	probes = append(probes, parameter)
+
+	go func() {
+		results := make([]*models.ProbeResult, len(probes))
+
+		stopped := runPool(ctx, len(probes), 1, 0, func(i int) {
+			results[i] = scanner.probeXXE(run, source, doc, probes[i], baseline, config.Processors)
+			run.probed(results[i])
+
+			if probes[i] == parameter && results[i].RequestID != "" {
+				scanner.listener.Attach(token.Token, parameter.Payload(), results[i].RequestID)
+			}
+		})
+
+		if !stopped {
+			select {
+			case <-ctx.Done():
+				stopped = true
+			case <-time.After(time.Duration(config.Wait)):
+			}
+		}
+
+		if result := results[len(results)-1]; result != nil && result.RequestID != "" {
+			scanner.collectDTDCallback(run, source, token.Token, result)
+		}
+
+		run.finish(stopped)
+	}()
+
+	return run.snapshot(), nil
+}
+
+func (scanner *Scanner) probeXXE(run *scanRun, source *network.HTTPRequest, doc *insertion.XMLDocument,
+	probe *scan.XXEProbe, baseline []byte, processors []*models.PayloadProcessor) *models.ProbeResult {
+	result := &models.ProbeResult{Name: probe.Name, Param: xxeDoctypeParam, Payload: probe.Payload()}
+	if probe.Leaf != nil {
+		result.Param = "xml:" + probe.Leaf.Path
+	}
+
+	request := scan.XXERequest(source, doc, probe)
+
+	body, err := fuzz.Process(string(request.Body), processors)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	request.Body = []byte(body)
+	if !run.scope.Allows(request) {
+		result.Error = models.ErrOutOfScope.Error()
+
+		return result
+	}
+
+	transaction, err := Repeat(run.storage, scanner.sender, request, source.ID)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	result.RequestID = transaction.Request.ID
+	result.Status = transaction.Response.Code
+
+	issueType, severity, finding := scan.CheckXXE(probe, baseline, transaction.Response)
+	if issueType == "" {
+		return result
+	}
+
+	result.Severity = severity
+	result.Finding = finding
+	result.IssueID = reportIssue(run.storage, &models.Issue{
+		Type:      issueType,
+		Severity:  severity,
+		Title:     xxeTitle(issueType),
+		Detail:    finding,
+		Host:      strings.ToLower(source.Host),
+		Path:      source.Path,
+		Param:     result.Param,
+		Evidence:  evidence(finding),
+		RequestID: result.RequestID,
+	})
+
+	return result
+}
+
+func xxeTitle(issueType string) string {
+	switch issueType {
+	case scan.IssueXMLEntities:
+		return "XML parser expands entities declared in the request"
+	case scan.IssueXMLParseError:
+		return "XML parser errors are shown to the client"
+	default:
+		return "XML parser loads external entities"
+	}
+}
+
+// collectDTDCallback reports a blind XXE when the parser fetched the external DTD.
+func (scanner *Scanner) collectDTDCallback(run *scanRun, source *network.HTTPRequest, token string,
+	result *models.ProbeResult) {
+	interactions := scanner.listener.Interactions(token)
+	if len(interactions) == 0 {
+		return
+	}
+
+	finding := fmt.Sprintf("external DTD fetched over %s from %s", interactions[0].Protocol, interactions[0].Source)
+
+	issueID := reportIssue(run.storage, &models.Issue{
+		Type:      scan.IssueXXE,
+		Severity:  models.SeverityHigh,
+		Title:     xxeTitle(scan.IssueXXE),
+		Detail:    finding,
+		Host:      strings.ToLower(source.Host),
+		Path:      source.Path,
+		Param:     xxeDoctypeParam,
+		Evidence:  evidence(interactions[0].Raw),
+		RequestID: result.RequestID,
+	})
+
+	run.mu.Lock()
+	result.Severity = models.SeverityHigh
+	result.Finding = finding
+	result.IssueID = issueID
+	run.mu.Unlock()
+}